@@ -0,0 +1,348 @@
+package annotator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/holydocs/servicefile/internal/parser"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Options controls where annotations are written.
+type Options struct {
+	// ServiceType is the type the service definition is written on.
+	// When empty, the service definition is written on the main package.
+	ServiceType string
+	// Targets maps relationships to the types they are written on.
+	// Keys are matched against "{action} {participant}", the participant or the action.
+	// Types can be qualified with the package name, e.g. "postgres.Connection".
+	// Relationships without a target are written next to the service definition.
+	Targets map[string]string
+	// Explicit writes relationships in the service:{service_name}:{action} form.
+	Explicit bool
+}
+
+// Change represents the modification of a single Go source file.
+type Change struct {
+	Path   string
+	Before []byte
+	After  []byte
+}
+
+// Diff returns the change as a unified diff.
+func (c Change) Diff() (string, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(c.Before)),
+		B:        difflib.SplitLines(string(c.After)),
+		FromFile: filepath.ToSlash(filepath.Join("a", c.Path)),
+		ToFile:   filepath.ToSlash(filepath.Join("b", c.Path)),
+		Context:  3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to make diff for %s: %w", c.Path, err)
+	}
+
+	return diff, nil
+}
+
+// Write writes the modified source back to the file.
+func (c Change) Write() error {
+	info, err := os.Stat(c.Path)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", c.Path, err)
+	}
+
+	if err := os.WriteFile(c.Path, c.After, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", c.Path, err)
+	}
+
+	return nil
+}
+
+type sourceFile struct {
+	path string
+	src  []byte
+	fset *token.FileSet
+	file *ast.File
+}
+
+// insertion is a comment block to be inserted at the offset of a source file.
+type insertion struct {
+	offset int
+	blocks []string
+}
+
+// Annotator writes annotation comment blocks describing a ServiceFile into Go source code.
+type Annotator struct {
+	files    []*sourceFile
+	existing map[string]bool
+}
+
+func NewAnnotator() *Annotator {
+	return &Annotator{
+		existing: make(map[string]bool),
+	}
+}
+
+// Annotate computes the changes needed to annotate the Go code in dir with sf.
+// Annotations already present in the code are not written again.
+func (a *Annotator) Annotate(dir string, recursive bool, sf *servicefile.ServiceFile, opts Options) ([]Change, error) {
+	if err := a.load(dir, recursive); err != nil {
+		return nil, err
+	}
+
+	insertions := make(map[*sourceFile]map[int]*insertion)
+
+	addBlock := func(f *sourceFile, offset int, block string) {
+		if insertions[f] == nil {
+			insertions[f] = make(map[int]*insertion)
+		}
+
+		if insertions[f][offset] == nil {
+			insertions[f][offset] = &insertion{offset: offset}
+		}
+
+		insertions[f][offset].blocks = append(insertions[f][offset].blocks, block)
+	}
+
+	serviceFile, serviceOffset, err := a.serviceTarget(opts.ServiceType)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName := ""
+	if opts.Explicit {
+		serviceName = sf.Info.Name
+	}
+
+	for _, r := range sf.Relationships {
+		block := parser.FormatRelationship(serviceName, r)
+		if a.exists(block) {
+			continue
+		}
+
+		target, ok := lookupTarget(opts.Targets, r)
+		if !ok {
+			addBlock(serviceFile, serviceOffset, block)
+			continue
+		}
+
+		f, offset, err := a.findType(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find target for %s %s: %w", r.Action, r.Participant, err)
+		}
+
+		addBlock(f, offset, block)
+	}
+
+	if block := parser.FormatService(sf.Info); !a.exists(block) {
+		addBlock(serviceFile, serviceOffset, block)
+	}
+
+	changes := make([]Change, 0, len(insertions))
+
+	for f, ins := range insertions {
+		after, err := apply(f, ins)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, Change{
+			Path:   f.path,
+			Before: f.src,
+			After:  after,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+func (a *Annotator) load(dir string, recursive bool) error {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk the path: %w", err)
+		}
+
+		if info.IsDir() && !recursive && path != dir {
+			return filepath.SkipDir
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		fset := token.NewFileSet()
+
+		file, err := goparser.ParseFile(fset, path, src, goparser.ParseComments)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		a.files = append(a.files, &sourceFile{path: path, src: src, fset: fset, file: file})
+
+		for _, cg := range file.Comments {
+			for _, line := range strings.Split(cg.Text(), "\n") {
+				a.existing[normalizeLine(line)] = true
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error walking the path: %w", err)
+	}
+
+	return nil
+}
+
+// exists reports whether the header line of the block is already present in the code.
+func (a *Annotator) exists(block string) bool {
+	lines := strings.Split(block, "\n")
+	if len(lines) < 2 {
+		return false
+	}
+
+	return a.existing[normalizeLine(lines[1])]
+}
+
+func normalizeLine(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// serviceTarget returns the place the service definition is written to.
+func (a *Annotator) serviceTarget(serviceType string) (*sourceFile, int, error) {
+	if serviceType != "" {
+		return a.findType(serviceType)
+	}
+
+	for _, f := range a.files {
+		if f.file.Name.Name != "main" {
+			continue
+		}
+
+		for _, decl := range f.file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+				return f, f.fset.Position(f.file.Package).Offset, nil
+			}
+		}
+	}
+
+	return nil, 0, fmt.Errorf("no main package found, specify the service type")
+}
+
+// findType returns the place a type declaration starts at.
+func (a *Annotator) findType(name string) (*sourceFile, int, error) {
+	pkg, typeName := "", name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		pkg, typeName = name[:i], name[i+1:]
+	}
+
+	var (
+		found  *sourceFile
+		offset int
+	)
+
+	for _, f := range a.files {
+		if pkg != "" && f.file.Name.Name != pkg {
+			continue
+		}
+
+		for _, decl := range f.file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Name.Name != typeName {
+					continue
+				}
+
+				if found != nil {
+					return nil, 0, fmt.Errorf("type %s is declared in both %s and %s, qualify it with the package name", name, found.path, f.path)
+				}
+
+				pos := gd.Pos()
+				if gd.Lparen.IsValid() {
+					pos = ts.Pos()
+				}
+
+				found, offset = f, f.fset.Position(pos).Offset
+			}
+		}
+	}
+
+	if found == nil {
+		return nil, 0, fmt.Errorf("type %s not found", name)
+	}
+
+	return found, offset, nil
+}
+
+func lookupTarget(targets map[string]string, r servicefile.Relationship) (string, bool) {
+	keys := []string{string(r.Action)}
+	if r.Participant != "" {
+		keys = []string{string(r.Action) + " " + r.Participant, r.Participant, string(r.Action)}
+	}
+
+	for _, key := range keys {
+		if target, ok := targets[key]; ok {
+			return target, true
+		}
+	}
+
+	return "", false
+}
+
+// apply inserts the comment blocks into the source and formats the result.
+func apply(f *sourceFile, insertions map[int]*insertion) ([]byte, error) {
+	offsets := make([]int, 0, len(insertions))
+	for offset := range insertions {
+		offsets = append(offsets, offset)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(offsets)))
+
+	src := f.src
+
+	for _, offset := range offsets {
+		// Blocks are separated by an empty line so each forms its own comment group.
+		text := strings.Join(insertions[offset].blocks, "\n")
+
+		var buf bytes.Buffer
+		buf.Write(src[:offset])
+		buf.WriteString(text)
+		buf.Write(src[offset:])
+		src = buf.Bytes()
+	}
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", f.path, err)
+	}
+
+	return formatted, nil
+}
@@ -0,0 +1,167 @@
+package annotator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/holydocs/servicefile/internal/parser/golang"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testServiceFile() *servicefile.ServiceFile {
+	return &servicefile.ServiceFile{
+		Version: servicefile.Version,
+		Info: servicefile.Info{
+			Name:        "Orders",
+			Description: "Handles orders",
+			Owner:       "team-orders",
+		},
+		Relationships: []servicefile.Relationship{
+			{
+				Action:      servicefile.RelationshipActionUses,
+				Participant: "PostgreSQL",
+				Description: "Stores orders",
+				Technology:  "postgresql",
+				Proto:       "tcp",
+			},
+			{
+				Action:      servicefile.RelationshipActionUses,
+				Participant: "Redis",
+				Technology:  "redis",
+			},
+			{
+				Action:      servicefile.RelationshipActionRequests,
+				Participant: "Payments",
+				Technology:  "http",
+			},
+		},
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	t.Parallel()
+
+	sf := testServiceFile()
+
+	changes, err := NewAnnotator().Annotate("testdata/service", true, sf, Options{
+		Targets: map[string]string{
+			"uses PostgreSQL": "Repository",
+			"Redis":           "storage.Cache",
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	assert.Equal(t, "testdata/service/main.go", changes[0].Path)
+	assert.Equal(t, `/*
+service:requests Payments
+technology: http
+*/
+
+/*
+service:name Orders
+description: Handles orders
+owner: team-orders
+*/
+package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("orders")
+}
+`, string(changes[0].After))
+
+	assert.Equal(t, "testdata/service/storage/storage.go", changes[1].Path)
+	assert.Equal(t, `package storage
+
+// Repository stores orders.
+/*
+service:uses PostgreSQL
+description: Stores orders
+technology: postgresql
+proto: tcp
+*/
+type Repository struct{}
+
+type (
+	/*
+	service:uses Redis
+	technology: redis
+	*/
+	Cache  struct{}
+	Client struct{}
+)
+`, string(changes[1].After))
+
+	diff, err := changes[0].Diff()
+	require.NoError(t, err)
+	assert.Contains(t, diff, "--- a/testdata/service/main.go")
+	assert.Contains(t, diff, "+service:name Orders")
+}
+
+func TestAnnotateRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.CopyFS(dir, os.DirFS("testdata/service")))
+
+	sf := testServiceFile()
+	opts := Options{
+		Targets: map[string]string{
+			"PostgreSQL": "Repository",
+			// Cache is declared in a grouped type declaration.
+			"Redis": "storage.Cache",
+		},
+	}
+
+	changes, err := NewAnnotator().Annotate(dir, true, sf, opts)
+	require.NoError(t, err)
+
+	for _, c := range changes {
+		require.NoError(t, c.Write())
+	}
+
+	parsed, err := golang.NewCommentParser().Parse(dir, true, false)
+	require.NoError(t, err)
+	require.Len(t, parsed, 1)
+
+	sf.Sort()
+	assert.Equal(t, sf, parsed[0])
+
+	changes, err = NewAnnotator().Annotate(dir, true, sf, opts)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestAnnotateErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		opts        Options
+		errContains string
+	}{
+		{
+			name:        "unknown service type",
+			opts:        Options{ServiceType: "Unknown"},
+			errContains: "type Unknown not found",
+		},
+		{
+			name:        "unknown target type",
+			opts:        Options{Targets: map[string]string{"Redis": "Unknown"}},
+			errContains: "failed to find target for uses Redis",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAnnotator().Annotate(filepath.Join("testdata", "service"), true, testServiceFile(), tt.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
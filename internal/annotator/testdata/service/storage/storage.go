@@ -0,0 +1,9 @@
+package storage
+
+// Repository stores orders.
+type Repository struct{}
+
+type (
+	Cache  struct{}
+	Client struct{}
+)
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"github.com/holydocs/servicefile/internal/api/cli/commands"
+	"github.com/holydocs/servicefile/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -12,7 +13,17 @@ func Command() *cobra.Command {
 
 	cmd.AddCommand(
 		commands.Parse(),
+		commands.Lint(),
+		commands.Annotate(),
+		commands.Import(),
+		commands.Export(),
+		commands.Merge(),
+		commands.Config(),
+		commands.Migrate(),
+		commands.Fmt(),
 	)
 
+	cmd.PersistentFlags().String("config", "", "Project configuration file, "+config.File+" of the working directory or of its closest parent in the repository by default")
+
 	return cmd
 }
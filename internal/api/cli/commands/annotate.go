@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/holydocs/servicefile/internal/annotator"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+	"github.com/spf13/cobra"
+)
+
+func Annotate() *cobra.Command {
+	var (
+		file          string
+		dir           string
+		recursive     bool
+		serviceType   string
+		relationships []string
+		explicit      bool
+		dryRun        bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "annotate",
+		Short: "Write annotation comment blocks from a servicefile into Go source",
+		Long: `Insert the service:name and service:<action> comment blocks describing an existing
+servicefile into Go source code. The service definition is written on the main package
+unless --service-type is set, relationships are written on the types given with
+--relationship and next to the service definition otherwise.`,
+		Example: `  servicefile annotate --file servicefile.yaml --relationship "PostgreSQL=postgres.Connection" --dry-run`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			targets, err := parseTargets(relationships)
+			if err != nil {
+				return err
+			}
+
+			opts := annotator.Options{
+				ServiceType: serviceType,
+				Targets:     targets,
+				Explicit:    explicit,
+			}
+
+			return annotateSource(file, dir, recursive, opts, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "servicefile.yaml", "ServiceFile to write annotations from")
+	cmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory to annotate")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", true, "Recursively annotate subdirectories")
+	cmd.Flags().StringVar(&serviceType, "service-type", "", "Type to write the service definition on instead of the main package")
+	cmd.Flags().StringArrayVar(&relationships, "relationship", nil,
+		`Type to write a relationship on, as "<action> <participant>=<type>", "<participant>=<type>" or "<action>=<type>"`)
+	cmd.Flags().BoolVar(&explicit, "explicit", false, "Write relationships in the service:{service_name}:{action} form")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print a patch instead of modifying files")
+
+	return cmd
+}
+
+func parseTargets(relationships []string) (map[string]string, error) {
+	targets := make(map[string]string, len(relationships))
+
+	for _, r := range relationships {
+		key, target, ok := strings.Cut(r, "=")
+		if !ok || strings.TrimSpace(key) == "" || strings.TrimSpace(target) == "" {
+			return nil, fmt.Errorf("invalid relationship target %q, expected <relationship>=<type>", r)
+		}
+
+		targets[strings.TrimSpace(key)] = strings.TrimSpace(target)
+	}
+
+	return targets, nil
+}
+
+func annotateSource(file, dir string, recursive bool, opts annotator.Options, dryRun bool) error {
+	sf, err := servicefile.Load(file)
+	if err != nil {
+		return fmt.Errorf("error loading service file: %w", err)
+	}
+
+	changes, err := annotator.NewAnnotator().Annotate(dir, recursive, sf, opts)
+	if err != nil {
+		return fmt.Errorf("error annotating source: %w", err)
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("Source is already annotated")
+		return nil
+	}
+
+	for _, c := range changes {
+		if dryRun {
+			diff, err := c.Diff()
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(diff)
+
+			continue
+		}
+
+		if err := c.Write(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Annotated: %s\n", c.Path)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/holydocs/servicefile/internal/config"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func Config() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the project configuration",
+	}
+
+	cmd.AddCommand(configPrint())
+
+	return cmd
+}
+
+func configPrint() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "print",
+		Short: "Print the effective configuration of parse",
+		Long: "Print the configuration parse runs with: the defaults, overridden by " + config.File +
+			", overridden by the flags.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, path, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			data, err := yaml.Marshal(cfg)
+			if err != nil {
+				return fmt.Errorf("error marshaling to YAML: %w", err)
+			}
+
+			if path != "" {
+				fmt.Printf("# Loaded from %s\n", path)
+			} else {
+				fmt.Printf("# No %s found, using the defaults\n", config.File)
+			}
+
+			fmt.Print(string(data))
+
+			return nil
+		},
+	}
+
+	addConfigFlags(cmd)
+
+	return cmd
+}
+
+// addConfigFlags registers the flags overriding the project configuration.
+func addConfigFlags(cmd *cobra.Command) {
+	defaults := config.Default()
+	flags := cmd.Flags()
+
+	flags.StringP("dir", "d", defaults.Dir, "Directory to analyze")
+	flags.BoolP("recursive", "r", defaults.Recursive, "Recursively analyze subdirectories")
+	flags.StringP("output", "o", defaults.Output,
+		"Output file path suffix for YAML, or a template like {{ lower .Name }}.servicefile.yaml")
+	flags.Bool("detect-repository", defaults.DetectRepository, "Automatically detect repository URL from git")
+	flags.StringSlice("include", nil, "Parse only the files matching the patterns")
+	flags.StringSlice("exclude", nil, "Skip the files and directories matching the patterns")
+	flags.StringSlice("lang", nil,
+		"Languages of the source code (go, typescript, python, java, kotlin, rust, csharp), detected by file extension if empty")
+	flags.String("owner", "", "Owner of the services that don't declare one")
+	flags.String("system", "", "System of the services that don't declare one")
+	flags.Bool("strict", defaults.Strict, "Fail on unknown annotation fields and relationship actions")
+	flags.String("extensions-schema", "", "Schema file to validate x-* extension fields against")
+	flags.String("overlay", "", "Overlay file patching the parsed servicefiles, "+servicefile.OverlayFile+" of the directory if it exists")
+}
+
+// loadConfig loads the project configuration given with --config, or found from the working
+// directory, and overrides it with the flags set on the command line. It returns the path of
+// the configuration file, empty if there is none.
+func loadConfig(cmd *cobra.Command) (*config.Config, string, error) {
+	path, err := cmd.Flags().GetString("config")
+	if err != nil || path == "" {
+		if path, err = config.Find("."); err != nil {
+			return nil, "", fmt.Errorf("error finding configuration: %w", err)
+		}
+	}
+
+	cfg := config.Default()
+
+	if path != "" {
+		if cfg, err = config.Load(path); err != nil {
+			return nil, "", fmt.Errorf("error loading configuration: %w", err)
+		}
+	}
+
+	flags := cmd.Flags()
+
+	for name, dst := range map[string]*string{
+		"dir":               &cfg.Dir,
+		"output":            &cfg.Output,
+		"owner":             &cfg.Defaults.Owner,
+		"system":            &cfg.Defaults.System,
+		"extensions-schema": &cfg.ExtensionsSchema,
+		"overlay":           &cfg.Overlay,
+	} {
+		if flags.Changed(name) {
+			*dst, _ = flags.GetString(name)
+		}
+	}
+
+	for name, dst := range map[string]*bool{
+		"recursive":         &cfg.Recursive,
+		"detect-repository": &cfg.DetectRepository,
+		"strict":            &cfg.Strict,
+	} {
+		if flags.Changed(name) {
+			*dst, _ = flags.GetBool(name)
+		}
+	}
+
+	for name, dst := range map[string]*[]string{
+		"include": &cfg.Include,
+		"exclude": &cfg.Exclude,
+		"lang":    &cfg.Languages,
+	} {
+		if flags.Changed(name) {
+			*dst, _ = flags.GetStringSlice(name)
+		}
+	}
+
+	return cfg, path, nil
+}
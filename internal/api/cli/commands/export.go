@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/holydocs/servicefile/internal/asyncapi"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func Export() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export servicefiles to other specifications",
+	}
+
+	cmd.AddCommand(exportAsyncAPI())
+
+	return cmd
+}
+
+func exportAsyncAPI() *cobra.Command {
+	var (
+		file   string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "asyncapi",
+		Short: "Export sends and receives relationships as a skeleton AsyncAPI document",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			sf, err := servicefile.Load(file)
+			if err != nil {
+				return fmt.Errorf("error loading service file: %w", err)
+			}
+
+			data, err := yaml.Marshal(asyncapi.Export(sf))
+			if err != nil {
+				return fmt.Errorf("error marshaling to YAML: %w", err)
+			}
+
+			if err := os.WriteFile(output, data, 0644); err != nil {
+				return fmt.Errorf("error writing to file: %w", err)
+			}
+
+			fmt.Printf("AsyncAPI document exported and saved to: %s\n", output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "servicefile.yaml", "Service file to export")
+	cmd.Flags().StringVarP(&output, "output", "o", "asyncapi.yaml", "Output file path for YAML")
+
+	return cmd
+}
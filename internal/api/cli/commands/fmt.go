@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+)
+
+// stdinName is the name the standard input is listed and diffed with, like gofmt does.
+const stdinName = "<standard input>"
+
+func Fmt() *cobra.Command {
+	var list, diff, write bool
+
+	cmd := &cobra.Command{
+		Use:   "fmt [file...]",
+		Short: "Format servicefiles canonically",
+		Long: `Format YAML servicefiles canonically, keeping their comments: fields in the order of the
+specification, relationships sorted, tags sorted and deduplicated, booleans written true
+or false and empty optional fields removed. Like gofmt, the formatted servicefiles are
+printed unless -l, -d or -w is set. Without files, servicefile.yaml is formatted and -
+reads the standard input.`,
+		Example: `  servicefile fmt -l docs/*.yaml
+  servicefile fmt -w servicefile.yaml`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			paths := args
+			if len(paths) == 0 {
+				paths = []string{"servicefile.yaml"}
+			}
+
+			for _, path := range paths {
+				if err := formatServiceFile(path, list, diff, write); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&list, "list", "l", false, "List the files whose formatting differs")
+	cmd.Flags().BoolVarP(&diff, "diff", "d", false, "Print diffs instead of the formatted files")
+	cmd.Flags().BoolVarP(&write, "write", "w", false, "Write the formatted files back instead of printing them")
+
+	return cmd
+}
+
+func formatServiceFile(path string, list, diff, write bool) error {
+	name := path
+
+	var (
+		data []byte
+		err  error
+	)
+
+	if path == servicefile.Stdin {
+		if write {
+			return errors.New("cannot use -w with the standard input")
+		}
+
+		name = stdinName
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		if format := servicefile.FormatFromPath(path); format != servicefile.FormatYAML {
+			return fmt.Errorf("cannot format %s: only YAML servicefiles are formatted, not %s", path, format)
+		}
+
+		data, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error reading service file: %w", err)
+	}
+
+	formatted, err := servicefile.Canonical(data)
+	if err != nil {
+		return fmt.Errorf("error formatting service file %s: %w", name, err)
+	}
+
+	changed := !bytes.Equal(data, formatted)
+
+	if list && changed {
+		fmt.Println(name)
+	}
+
+	if diff && changed {
+		d, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(data)),
+			B:        difflib.SplitLines(string(formatted)),
+			FromFile: filepath.ToSlash(filepath.Join("a", name)),
+			ToFile:   filepath.ToSlash(filepath.Join("b", name)),
+			Context:  3,
+		})
+		if err != nil {
+			return fmt.Errorf("error making diff for %s: %w", name, err)
+		}
+
+		fmt.Print(d)
+	}
+
+	if write && changed {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("error writing to file: %w", err)
+		}
+
+		if err := os.WriteFile(path, formatted, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("error writing to file: %w", err)
+		}
+	}
+
+	if !list && !diff && !write {
+		fmt.Print(string(formatted))
+	}
+
+	return nil
+}
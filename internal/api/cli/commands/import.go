@@ -0,0 +1,272 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/holydocs/servicefile/internal/asyncapi"
+	"github.com/holydocs/servicefile/internal/compose"
+	"github.com/holydocs/servicefile/internal/kubernetes"
+	"github.com/holydocs/servicefile/internal/openapi"
+	"github.com/holydocs/servicefile/internal/terraform"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+	"github.com/spf13/cobra"
+)
+
+func Import() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import servicefiles from other specifications",
+	}
+
+	cmd.AddCommand(
+		importAsyncAPI(),
+		importOpenAPI(),
+		importCompose(),
+		importKubernetes(),
+		importTerraform(),
+	)
+
+	return cmd
+}
+
+func importAsyncAPI() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "asyncapi <file>",
+		Short: "Import sends and receives relationships from an AsyncAPI document",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			doc, err := asyncapi.Load(args[0])
+			if err != nil {
+				return fmt.Errorf("error loading AsyncAPI document: %w", err)
+			}
+
+			sf, err := asyncapi.Import(doc)
+			if err != nil {
+				return fmt.Errorf("error importing AsyncAPI document: %w", err)
+			}
+
+			if err := saveServiceFile(sf, output); err != nil {
+				return fmt.Errorf("error saving service file to %s: %w", output, err)
+			}
+
+			fmt.Printf("ServiceFile imported and saved to: %s\n", output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "servicefile.yaml", "Output file path for YAML")
+
+	return cmd
+}
+
+func importOpenAPI() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "openapi <file>",
+		Short: "Import a replies relationship from an OpenAPI document",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			doc, err := openapi.Load(args[0])
+			if err != nil {
+				return fmt.Errorf("error loading OpenAPI document: %w", err)
+			}
+
+			spec, err := filepath.Abs(args[0])
+			if err != nil {
+				return fmt.Errorf("error resolving OpenAPI document path: %w", err)
+			}
+
+			sf := openapi.Import(doc)
+			sf.Relationships[0].Spec = spec
+
+			if err := saveServiceFile(sf, output); err != nil {
+				return fmt.Errorf("error saving service file to %s: %w", output, err)
+			}
+
+			fmt.Printf("ServiceFile imported and saved to: %s\n", output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "servicefile.yaml", "Output file path for YAML")
+
+	return cmd
+}
+
+func importCompose() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "compose [file...]",
+		Short: "Import draft servicefiles from docker compose files",
+		Long: "Import a draft servicefile for every service built from source, with uses relationships " +
+			"for its depends_on and links. Later files override earlier ones, like docker compose -f. " +
+			"Without files, the default compose file of the working directory is read.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			paths := args
+			if len(paths) == 0 {
+				path, err := defaultComposeFile()
+				if err != nil {
+					return err
+				}
+
+				paths = []string{path}
+			}
+
+			f, err := compose.Load(paths...)
+			if err != nil {
+				return fmt.Errorf("error loading compose files: %w", err)
+			}
+
+			serviceFiles := compose.Import(f)
+			if len(serviceFiles) == 0 {
+				return fmt.Errorf("no services with a build section found in the compose files")
+			}
+
+			return saveServiceFiles(serviceFiles, output, "imported")
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "servicefile.yaml", "Output file path suffix for YAML")
+
+	return cmd
+}
+
+func importKubernetes() *cobra.Command {
+	var (
+		output           string
+		ownerAnnotations []string
+		report           string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "kubernetes <dir>",
+		Aliases: []string{"k8s"},
+		Short:   "Import servicefiles from rendered Kubernetes manifests",
+		Long: "Import a servicefile for every Deployment and StatefulSet of the manifests found in dir, " +
+			"e.g. the output of helm template. Environment variables and ConfigMaps referencing the DNS name " +
+			"of a Service become requests relationships to the workload it selects.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			m, err := kubernetes.Load(args[0])
+			if err != nil {
+				return fmt.Errorf("error loading manifests: %w", err)
+			}
+
+			serviceFiles, inferences := kubernetes.Import(m, ownerAnnotations)
+			if len(serviceFiles) == 0 {
+				return fmt.Errorf("no Deployments or StatefulSets found in %s", args[0])
+			}
+
+			if err := saveServiceFiles(serviceFiles, output, "imported"); err != nil {
+				return err
+			}
+
+			return writeReport(inferences, report)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "servicefile.yaml", "Output file path suffix for YAML")
+	cmd.Flags().StringSliceVar(&ownerAnnotations, "owner-annotation", kubernetes.DefaultOwnerAnnotations,
+		"Annotations the owner of a service is read from, in order")
+	cmd.Flags().StringVar(&report, "report", "", "File to write the report of inferred values to instead of stdout")
+
+	return cmd
+}
+
+func importTerraform() *cobra.Command {
+	var (
+		tagKeys []string
+		report  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "terraform <show.json> [servicefile...]",
+		Short: "Merge managed resources of a Terraform state or plan into servicefiles",
+		Long: "Merge the databases, queues, topics and buckets of the output of terraform show -json into " +
+			"the servicefiles they belong to, as external relationships. A resource belongs to the service " +
+			"named by its tags or labels, or else to the service its name contains. " +
+			"Without servicefiles, servicefile.yaml of the working directory is updated.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			state, err := terraform.Load(args[0])
+			if err != nil {
+				return fmt.Errorf("error loading Terraform output: %w", err)
+			}
+
+			paths := args[1:]
+			if len(paths) == 0 {
+				paths = []string{"servicefile.yaml"}
+			}
+
+			serviceFiles := make([]*servicefile.ServiceFile, 0, len(paths))
+
+			for _, path := range paths {
+				sf, err := servicefile.Load(path)
+				if err != nil {
+					return fmt.Errorf("error loading service file: %w", err)
+				}
+
+				serviceFiles = append(serviceFiles, sf)
+			}
+
+			results := terraform.Merge(serviceFiles, state.Resources(), tagKeys)
+
+			for i, sf := range serviceFiles {
+				if err := saveServiceFile(sf, paths[i]); err != nil {
+					return fmt.Errorf("error saving service file to %s: %w", paths[i], err)
+				}
+
+				fmt.Printf("ServiceFile for '%s' merged and saved to: %s\n", sf.Info.Name, paths[i])
+			}
+
+			return writeReport(results, report)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&tagKeys, "tag", terraform.DefaultTagKeys,
+		"Tags and labels the service owning a resource is read from, in order")
+	cmd.Flags().StringVar(&report, "report", "", "File to write the report of merged resources to instead of stdout")
+
+	return cmd
+}
+
+// writeReport writes what an importer inferred to the report file, or to stdout if it's empty.
+func writeReport[T fmt.Stringer](inferences []T, report string) error {
+	var b strings.Builder
+	for _, inference := range inferences {
+		b.WriteString(inference.String())
+		b.WriteString("\n")
+	}
+
+	if report == "" {
+		fmt.Printf("\nInferred:\n%s", b.String())
+		return nil
+	}
+
+	if err := os.WriteFile(report, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("error writing report to %s: %w", report, err)
+	}
+
+	fmt.Printf("Report saved to: %s\n", report)
+
+	return nil
+}
+
+func defaultComposeFile() (string, error) {
+	for _, path := range compose.DefaultFiles {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("no compose file found, expected one of %v", compose.DefaultFiles)
+}
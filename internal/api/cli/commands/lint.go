@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/holydocs/servicefile/internal/parser"
+	"github.com/holydocs/servicefile/internal/parser/golang"
+	"github.com/spf13/cobra"
+)
+
+func Lint() *cobra.Command {
+	var (
+		dir       string
+		recursive bool
+		suggest   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Find undocumented and stale relationships",
+		Long: `Compare what the code evidently talks to (database drivers, message broker clients,
+gRPC clients, outbound HTTP clients) with the relationships declared in annotations
+and report the missing and stale ones.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return lintServiceFiles(dir, recursive, suggest)
+		},
+	}
+
+	cmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory to analyze")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", true, "Recursively analyze subdirectories")
+	cmd.Flags().BoolVar(&suggest, "suggest", false, "Print annotation comment blocks for missing relationships")
+
+	return cmd
+}
+
+func lintServiceFiles(dir string, recursive bool, suggest bool) error {
+	findings, err := golang.NewCommentParser().Lint(dir, recursive)
+	if err != nil {
+		return fmt.Errorf("error linting source: %w", err)
+	}
+
+	if len(findings) == 0 {
+		fmt.Println("No issues found")
+		return nil
+	}
+
+	for _, f := range findings {
+		fmt.Println(f)
+
+		if suggest && f.Kind == golang.FindingKindMissing {
+			if f.Type != "" {
+				fmt.Printf("Add to type %s in %s:\n", f.Type, f.File)
+			} else {
+				fmt.Printf("Add to %s:\n", f.File)
+			}
+
+			fmt.Println(parser.FormatRelationship(f.Service, f.Relationship))
+
+			if f.Relationship.Participant == golang.ParticipantPlaceholder {
+				fmt.Printf("Replace %s with the name of the service the client talks to.\n", golang.ParticipantPlaceholder)
+			}
+		}
+	}
+
+	return fmt.Errorf("found %d issue(s)", len(findings))
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+	"github.com/spf13/cobra"
+)
+
+func Merge() *cobra.Command {
+	var (
+		output string
+		strict bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "merge <file> <file>...",
+		Short: "Merge servicefiles of the same service into one",
+		Long: "Merge servicefiles parsed from annotations, imported and written by hand into one. " +
+			"Earlier files take precedence: a field is set by the first file setting it, and different " +
+			"values of later files are reported as conflicts. Tags, operations and messages are merged, " +
+			"and relationships with the same action, participant, technology, proto and channel are deduplicated.",
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			serviceFiles := make([]*servicefile.ServiceFile, 0, len(args))
+
+			for _, path := range args {
+				sf, err := servicefile.Load(path)
+				if err != nil {
+					return fmt.Errorf("error loading service file: %w", err)
+				}
+
+				serviceFiles = append(serviceFiles, sf)
+			}
+
+			merged, conflicts := servicefile.Merge(serviceFiles...)
+
+			for _, conflict := range conflicts {
+				fmt.Fprintf(os.Stderr, "conflict in %s: %s\n", args[conflict.Index], conflict)
+			}
+
+			if strict && len(conflicts) > 0 {
+				return fmt.Errorf("found %d conflicts", len(conflicts))
+			}
+
+			if err := saveServiceFile(merged, output); err != nil {
+				return fmt.Errorf("error saving service file to %s: %w", output, err)
+			}
+
+			fmt.Printf("ServiceFile merged and saved to: %s\n", output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "servicefile.yaml", "Output file path for YAML")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail on conflicts instead of keeping the value of the earlier file")
+
+	return cmd
+}
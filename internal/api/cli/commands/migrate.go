@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func Migrate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [file...]",
+		Short: "Upgrade servicefiles to the current specification version",
+		Long: "Upgrade servicefiles in place, step by step, to version " + servicefile.Version +
+			" of the specification. Comments of YAML files are kept. Without files, servicefile.yaml is upgraded.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			paths := args
+			if len(paths) == 0 {
+				paths = []string{"servicefile.yaml"}
+			}
+
+			for _, path := range paths {
+				if err := migrateServiceFile(path); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func migrateServiceFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading service file: %w", err)
+	}
+
+	format := servicefile.FormatFromPath(path)
+
+	doc, err := servicefile.UnmarshalNode(data, format)
+	if err != nil {
+		return fmt.Errorf("error parsing service file %s: %w", path, err)
+	}
+
+	declared, err := servicefile.Migrate(doc)
+	if err != nil {
+		return fmt.Errorf("error migrating service file %s: %w", path, err)
+	}
+
+	var migrated struct {
+		Version string `yaml:"servicefile"`
+	}
+
+	if err := doc.Decode(&migrated); err != nil {
+		return fmt.Errorf("error parsing service file %s: %w", path, err)
+	}
+
+	if declared == servicefile.Version || migrated.Version != servicefile.Version {
+		fmt.Printf("ServiceFile %s is up to date\n", path)
+		return nil
+	}
+
+	if format == servicefile.FormatYAML {
+		data, err = yaml.Marshal(doc)
+	} else {
+		var sf servicefile.ServiceFile
+		if err := doc.Decode(&sf); err != nil {
+			return fmt.Errorf("error parsing service file %s: %w", path, err)
+		}
+
+		data, err = sf.Marshal(format)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error marshaling to %s: %w", strings.ToUpper(string(format)), err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("error writing to file: %w", err)
+	}
+
+	fmt.Printf("ServiceFile %s migrated from %s to %s\n", path, declared, servicefile.Version)
+
+	return nil
+}
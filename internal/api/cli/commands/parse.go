@@ -1,44 +1,61 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"text/template"
 
+	"github.com/holydocs/servicefile/internal/config"
+	"github.com/holydocs/servicefile/internal/parser"
 	"github.com/holydocs/servicefile/internal/parser/golang"
+	"github.com/holydocs/servicefile/pkg/language"
 	"github.com/holydocs/servicefile/pkg/servicefile"
+
+	// Register the languages parsed from comments.
+	_ "github.com/holydocs/servicefile/internal/parser/csharp"
+	_ "github.com/holydocs/servicefile/internal/parser/jvm"
+	_ "github.com/holydocs/servicefile/internal/parser/python"
+	_ "github.com/holydocs/servicefile/internal/parser/rust"
+	_ "github.com/holydocs/servicefile/internal/parser/typescript"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 func Parse() *cobra.Command {
-	var (
-		dir              string
-		recursive        bool
-		output           string
-		detectRepository bool
-	)
-
 	cmd := &cobra.Command{
 		Use:   "parse",
 		Short: "Parse servicefiles from source",
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return parseServiceFiles(dir, recursive, output, detectRepository)
+		Long: "Parse servicefiles from source. The flags override the project configuration " +
+			"read from " + config.File + " of the working directory or of its closest parent in the repository.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, _, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			return parseServiceFiles(cfg)
 		},
 	}
 
-	cmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory to analyze")
-	cmd.Flags().BoolVarP(&recursive, "recursive", "r", true, "Recursively analyze subdirectories")
-	cmd.Flags().StringVarP(&output, "output", "o", "servicefile.yaml", "Output file path suffix for YAML")
-	cmd.Flags().BoolVar(&detectRepository, "detect-repository", true, "Automatically detect repository URL from git")
+	addConfigFlags(cmd)
 
 	return cmd
 }
 
-func parseServiceFiles(dir string, recursive bool, output string, detectRepository bool) error {
-	parser := golang.NewCommentParser()
+func parseServiceFiles(cfg *config.Config) error {
+	languages, err := selectLanguages(cfg.Languages)
+	if err != nil {
+		return err
+	}
 
-	serviceFiles, err := parser.Parse(dir, recursive, detectRepository)
+	serviceFiles, err := parser.ParseDir(context.Background(), cfg.Dir, parser.Config{
+		Recursive: cfg.Recursive,
+		Include:   cfg.Include,
+		Exclude:   cfg.Exclude,
+		Strict:    cfg.Strict,
+	}, cfg.DetectRepository, languages...)
 	if err != nil {
 		return fmt.Errorf("error parsing service file: %w", err)
 	}
@@ -47,41 +64,261 @@ func parseServiceFiles(dir string, recursive bool, output string, detectReposito
 		return fmt.Errorf("no services found in the specified directory")
 	}
 
+	cfg.Apply(serviceFiles)
+
+	// Specs are relative to the parsed directory, saveServiceFile makes them relative to the output.
+	for _, sf := range serviceFiles {
+		for i, rel := range sf.Relationships {
+			if rel.Spec != "" && !filepath.IsAbs(rel.Spec) {
+				spec, err := filepath.Abs(filepath.Join(cfg.Dir, filepath.FromSlash(rel.Spec)))
+				if err != nil {
+					return fmt.Errorf("error resolving spec %s: %w", rel.Spec, err)
+				}
+
+				sf.Relationships[i].Spec = spec
+			}
+		}
+	}
+
+	if err := applyOverlay(serviceFiles, cfg.Dir, cfg.Overlay); err != nil {
+		return err
+	}
+
+	if cfg.ExtensionsSchema != "" {
+		schema, err := servicefile.LoadExtensionSchema(cfg.ExtensionsSchema)
+		if err != nil {
+			return fmt.Errorf("error loading extensions schema: %w", err)
+		}
+
+		for _, sf := range serviceFiles {
+			if err := schema.Validate(sf); err != nil {
+				return fmt.Errorf("invalid extension fields: %w", err)
+			}
+		}
+	}
+
+	return saveServiceFiles(serviceFiles, cfg.Output, "generated")
+}
+
+// applyOverlay patches the service files with the overlay file, or with the default overlay
+// file of dir if it exists.
+func applyOverlay(serviceFiles []*servicefile.ServiceFile, dir, path string) error {
+	if path == "" {
+		path = filepath.Join(dir, servicefile.OverlayFile)
+		if _, err := os.Stat(path); err != nil {
+			return nil
+		}
+	}
+
+	overlay, err := servicefile.LoadOverlay(path)
+	if err != nil {
+		return fmt.Errorf("error loading overlay: %w", err)
+	}
+
+	for _, name := range overlay.Apply(serviceFiles) {
+		fmt.Fprintf(os.Stderr, "overlay %s: no service named %q found\n", path, name)
+	}
+
+	return nil
+}
+
+// saveServiceFiles saves a single service file to output and several service files
+// to output prefixed with the lowercased name of their service. An output containing
+// {{ is a text/template executed with the info of every service instead, e.g.
+// docs/{{ lower .Name }}/servicefile.yaml.
+func saveServiceFiles(serviceFiles []*servicefile.ServiceFile, output, verb string) error {
+	if strings.Contains(output, "{{") {
+		return saveServiceFilesToTemplate(serviceFiles, output, verb)
+	}
+
 	if len(serviceFiles) == 1 {
 		sf := serviceFiles[0]
 
-		if err := saveServiceFileToYAML(sf, output); err != nil {
+		if err := saveServiceFile(sf, output); err != nil {
 			return fmt.Errorf("error saving service file to %s: %w", output, err)
 		}
 
-		fmt.Printf("ServiceFile generated and saved to: %s\n", output)
+		fmt.Printf("ServiceFile %s and saved to: %s\n", verb, output)
 
 		return nil
 	}
 
 	for _, sf := range serviceFiles {
-		filepath := fmt.Sprintf("%s.%s", strings.ToLower(sf.Info.Name), output)
+		path := filepath.Join(filepath.Dir(output), fmt.Sprintf("%s.%s", strings.ToLower(sf.Info.Name), filepath.Base(output)))
+
+		if err := saveServiceFile(sf, path); err != nil {
+			return fmt.Errorf("error saving service file to %s: %w", path, err)
+		}
+
+		fmt.Printf("ServiceFile for '%s' %s and saved to: %s\n", sf.Info.Name, verb, path)
+	}
+
+	return nil
+}
+
+// outputFuncs are the functions available to output templates.
+var outputFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+}
+
+func saveServiceFilesToTemplate(serviceFiles []*servicefile.ServiceFile, output, verb string) error {
+	tmpl, err := template.New("output").Funcs(outputFuncs).Option("missingkey=error").Parse(output)
+	if err != nil {
+		return fmt.Errorf("invalid output template %q: %w", output, err)
+	}
+
+	saved := make(map[string]string, len(serviceFiles))
+
+	for _, sf := range serviceFiles {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, sf.Info); err != nil {
+			return fmt.Errorf("error executing output template for '%s': %w", sf.Info.Name, err)
+		}
+
+		path := b.String()
+		if other, ok := saved[path]; ok {
+			return fmt.Errorf("output template %q saves both '%s' and '%s' to %s", output, other, sf.Info.Name, path)
+		}
+
+		saved[path] = sf.Info.Name
 
-		if err := saveServiceFileToYAML(sf, filepath); err != nil {
-			return fmt.Errorf("error saving service file to %s: %w", filepath, err)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("error creating directory for %s: %w", path, err)
 		}
 
-		fmt.Printf("ServiceFile for '%s' generated and saved to: %s\n", sf.Info.Name, filepath)
+		if err := saveServiceFile(sf, path); err != nil {
+			return fmt.Errorf("error saving service file to %s: %w", path, err)
+		}
+
+		fmt.Printf("ServiceFile for '%s' %s and saved to: %s\n", sf.Info.Name, verb, path)
 	}
 
 	return nil
 }
 
-func saveServiceFileToYAML(sf *servicefile.ServiceFile, filepath string) error {
-	yamlData, err := yaml.Marshal(sf)
+// languageAliases maps alternative names of languages to the names they are selected by.
+var languageAliases = map[string]string{
+	"golang":     "go",
+	"ts":         "typescript",
+	"js":         "typescript",
+	"javascript": "typescript",
+	"py":         "python",
+	"kt":         "kotlin",
+	"rs":         "rust",
+	"cs":         "csharp",
+	"c#":         "csharp",
+}
+
+// selectLanguages returns the named languages, or all supported languages if there are none.
+// Go is parsed by its own parser, the other languages come from the language registry.
+func selectLanguages(names []string) ([]parser.Language, error) {
+	goParser := golang.NewCommentParser()
+
+	if len(names) == 0 {
+		languages := []parser.Language{goParser}
+
+		for _, l := range language.Default.Languages() {
+			// All the extensions of the language may be taken over by other languages.
+			if extensions := language.Default.Extensions(l.Name()); len(extensions) > 0 {
+				languages = append(languages, parser.FromComments(l, extensions...))
+			}
+		}
+
+		return languages, nil
+	}
+
+	var (
+		languages []parser.Language
+		selected  = make(map[string]bool)
+	)
+
+	for _, lang := range names {
+		name := strings.ToLower(lang)
+		if alias, ok := languageAliases[name]; ok {
+			name = alias
+		}
+
+		if selected[name] {
+			continue
+		}
+
+		selected[name] = true
+
+		if name == goParser.Name() {
+			languages = append(languages, goParser)
+			continue
+		}
+
+		l, ok := language.Default.Get(name)
+		if !ok {
+			supported := []string{goParser.Name()}
+			for _, l := range language.Default.Languages() {
+				supported = append(supported, l.Name())
+			}
+
+			return nil, fmt.Errorf("unsupported language %q, supported languages: %s", lang, strings.Join(supported, ", "))
+		}
+
+		languages = append(languages, parser.FromComments(l))
+	}
+
+	return languages, nil
+}
+
+// saveServiceFile saves the service file in the format of the path extension, YAML by default.
+// Absolute specs of its relationships are written relative to the directory of the saved file.
+func saveServiceFile(sf *servicefile.ServiceFile, path string) error {
+	format := servicefile.FormatFromPath(path)
+
+	if hasAbsoluteSpec(sf) {
+		rebased := *sf
+		rebased.Relationships = append([]servicefile.Relationship(nil), sf.Relationships...)
+
+		for i, rel := range rebased.Relationships {
+			if filepath.IsAbs(rel.Spec) {
+				rebased.Relationships[i].Spec = specPath(rel.Spec, path)
+			}
+		}
+
+		sf = &rebased
+	}
+
+	data, err := sf.Marshal(format)
 	if err != nil {
-		return fmt.Errorf("error marshaling to YAML: %w", err)
+		return fmt.Errorf("error marshaling to %s: %w", strings.ToUpper(string(format)), err)
 	}
 
-	err = os.WriteFile(filepath, yamlData, 0644)
+	err = os.WriteFile(path, data, 0644)
 	if err != nil {
 		return fmt.Errorf("error writing to file: %w", err)
 	}
 
 	return nil
 }
+
+// hasAbsoluteSpec reports whether a relationship of the service file has an absolute spec.
+func hasAbsoluteSpec(sf *servicefile.ServiceFile) bool {
+	for _, rel := range sf.Relationships {
+		if filepath.IsAbs(rel.Spec) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// specPath returns the absolute path of a document relative to the directory of the servicefile
+// written to output, or the absolute path when there is no relative one.
+func specPath(abs, output string) string {
+	dir, err := filepath.Abs(filepath.Dir(output))
+	if err != nil {
+		return abs
+	}
+
+	rel, err := filepath.Rel(dir, abs)
+	if err != nil {
+		return abs
+	}
+
+	return filepath.ToSlash(rel)
+}
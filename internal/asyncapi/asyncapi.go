@@ -0,0 +1,212 @@
+package asyncapi
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document represents an AsyncAPI 2.x or 3.x document.
+// Only the parts describing channels and their operations are modelled.
+type Document struct {
+	AsyncAPI   string               `yaml:"asyncapi"`
+	Info       Info                 `yaml:"info"`
+	Servers    map[string]Server    `yaml:"servers,omitempty"`
+	Channels   map[string]Channel   `yaml:"channels,omitempty"`
+	Operations map[string]Operation `yaml:"operations,omitempty"`
+	Components *Components          `yaml:"components,omitempty"`
+}
+
+// Info represents the info of the application.
+type Info struct {
+	Title       string `yaml:"title"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// Server represents a message broker.
+type Server struct {
+	// URL is used by AsyncAPI 2.x.
+	URL string `yaml:"url,omitempty"`
+	// Host is used by AsyncAPI 3.x.
+	Host        string `yaml:"host,omitempty"`
+	Protocol    string `yaml:"protocol"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// Channel represents a channel. AsyncAPI 2.x channels carry their operations,
+// AsyncAPI 3.x channels carry their address and messages.
+type Channel struct {
+	Address     string             `yaml:"address,omitempty"`
+	Description string             `yaml:"description,omitempty"`
+	Servers     []ServerReference  `yaml:"servers,omitempty"`
+	Messages    map[string]Message `yaml:"messages,omitempty"`
+	Publish     *ChannelOperation  `yaml:"publish,omitempty"`
+	Subscribe   *ChannelOperation  `yaml:"subscribe,omitempty"`
+	Bindings    map[string]any     `yaml:"bindings,omitempty"`
+}
+
+// ChannelOperation represents an AsyncAPI 2.x publish or subscribe operation.
+type ChannelOperation struct {
+	OperationID string         `yaml:"operationId,omitempty"`
+	Summary     string         `yaml:"summary,omitempty"`
+	Description string         `yaml:"description,omitempty"`
+	Message     *Message       `yaml:"message,omitempty"`
+	Bindings    map[string]any `yaml:"bindings,omitempty"`
+}
+
+// Operation represents an AsyncAPI 3.x operation.
+type Operation struct {
+	Action      string         `yaml:"action"`
+	Channel     Reference      `yaml:"channel"`
+	Summary     string         `yaml:"summary,omitempty"`
+	Description string         `yaml:"description,omitempty"`
+	Messages    []Reference    `yaml:"messages,omitempty"`
+	Bindings    map[string]any `yaml:"bindings,omitempty"`
+}
+
+// Message represents a message, a reference to one or a list of alternatives.
+type Message struct {
+	Ref     string         `yaml:"$ref,omitempty"`
+	Name    string         `yaml:"name,omitempty"`
+	Title   string         `yaml:"title,omitempty"`
+	Payload map[string]any `yaml:"payload,omitempty"`
+	OneOf   []Message      `yaml:"oneOf,omitempty"`
+}
+
+// Reference represents a JSON reference.
+type Reference struct {
+	Ref string `yaml:"$ref"`
+}
+
+// ServerReference references a server by name (AsyncAPI 2.x) or by a JSON reference (AsyncAPI 3.x).
+type ServerReference struct {
+	Name string
+	Ref  string
+}
+
+// UnmarshalYAML decodes a server name or a server reference.
+func (s *ServerReference) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		s.Name = value.Value
+		return nil
+	}
+
+	var ref Reference
+	if err := value.Decode(&ref); err != nil {
+		return err
+	}
+
+	s.Ref = ref.Ref
+	s.Name = lastRefSegment(ref.Ref)
+
+	return nil
+}
+
+// MarshalYAML encodes the server reference in the AsyncAPI 3.x form.
+func (s ServerReference) MarshalYAML() (any, error) {
+	if s.Ref == "" {
+		return s.Name, nil
+	}
+
+	return Reference{Ref: s.Ref}, nil
+}
+
+// Components holds reusable objects.
+type Components struct {
+	Messages map[string]Message `yaml:"messages,omitempty"`
+	Schemas  map[string]any     `yaml:"schemas,omitempty"`
+}
+
+// MajorVersion returns the major version of the AsyncAPI specification the document uses.
+func (d *Document) MajorVersion() string {
+	major, _, _ := strings.Cut(d.AsyncAPI, ".")
+	return major
+}
+
+// Load reads and parses an AsyncAPI document from a YAML or JSON file at the given path.
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", path, err)
+	}
+
+	switch doc.MajorVersion() {
+	case "2", "3":
+	default:
+		return nil, fmt.Errorf("unsupported AsyncAPI version %q in %s", doc.AsyncAPI, path)
+	}
+
+	return &doc, nil
+}
+
+// resolveMessage follows a local message reference.
+func (d *Document) resolveMessage(m Message) Message {
+	if m.Ref == "" {
+		return m
+	}
+
+	name := lastRefSegment(m.Ref)
+
+	if d.Components != nil {
+		if resolved, ok := d.Components.Messages[name]; ok && resolved.Ref == "" {
+			if resolved.Name == "" {
+				resolved.Name = name
+			}
+
+			return resolved
+		}
+	}
+
+	// References to channel messages in AsyncAPI 3.x: #/channels/{channel}/messages/{message}.
+	if parts := refSegments(m.Ref); len(parts) == 4 && parts[0] == "channels" && parts[2] == "messages" {
+		if resolved, ok := d.Channels[parts[1]].Messages[parts[3]]; ok && resolved.Ref != m.Ref {
+			resolved = d.resolveMessage(resolved)
+			if resolved.Name == "" {
+				resolved.Name = parts[3]
+			}
+
+			return resolved
+		}
+	}
+
+	return Message{Ref: m.Ref, Name: name}
+}
+
+func lastRefSegment(ref string) string {
+	parts := refSegments(ref)
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return parts[len(parts)-1]
+}
+
+// refSegments splits a local JSON reference into unescaped JSON pointer segments.
+func refSegments(ref string) []string {
+	pointer := strings.TrimPrefix(ref, "#/")
+	if pointer == "" {
+		return nil
+	}
+
+	parts := strings.Split(pointer, "/")
+	for i, part := range parts {
+		part = strings.ReplaceAll(part, "~1", "/")
+		parts[i] = strings.ReplaceAll(part, "~0", "~")
+	}
+
+	return parts
+}
+
+// escapeRefSegment escapes a JSON pointer segment.
+func escapeRefSegment(segment string) string {
+	segment = strings.ReplaceAll(segment, "~", "~0")
+	return strings.ReplaceAll(segment, "/", "~1")
+}
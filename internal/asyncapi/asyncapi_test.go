@@ -0,0 +1,231 @@
+package asyncapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+func TestImportV2(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load(filepath.Join("testdata", "v2.yaml"))
+	require.NoError(t, err)
+
+	sf, err := Import(doc)
+	require.NoError(t, err)
+
+	assert.Equal(t, servicefile.Info{Name: "OrderService", Description: "Handles orders"}, sf.Info)
+	assert.ElementsMatch(t, []servicefile.Relationship{
+		{
+			Action:      servicefile.RelationshipActionSends,
+			Participant: "orders.created",
+			Description: "Publishes created orders",
+			Technology:  "kafka",
+			Proto:       "kafka-secure",
+			Channel: &servicefile.Channel{
+				Name:     "orders.created",
+				Messages: []string{"OrderCreated"},
+				Schema:   "#/components/schemas/Order",
+			},
+		},
+		{
+			Action:      servicefile.RelationshipActionReceives,
+			Participant: "payments.completed",
+			Description: "Consumes completed payments",
+			Technology:  "kafka",
+			Proto:       "kafka-secure",
+			Channel: &servicefile.Channel{
+				Name:     "payments.completed",
+				Messages: []string{"PaymentCompleted", "payment.failed"},
+			},
+		},
+	}, sf.Relationships)
+}
+
+func TestImportV3(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load(filepath.Join("testdata", "v3.yaml"))
+	require.NoError(t, err)
+
+	sf, err := Import(doc)
+	require.NoError(t, err)
+
+	assert.Equal(t, "NotificationService", sf.Info.Name)
+	assert.ElementsMatch(t, []servicefile.Relationship{
+		{
+			Action:      servicefile.RelationshipActionReceives,
+			Participant: "user/signedup",
+			Technology:  "nats",
+			Proto:       "nats",
+			Channel: &servicefile.Channel{
+				Name:     "user/signedup",
+				Messages: []string{"UserSignedUp"},
+				Schema:   "#/components/schemas/User",
+			},
+		},
+		{
+			Action:      servicefile.RelationshipActionSends,
+			Participant: "emails",
+			Description: "Outgoing emails",
+			Technology:  "rabbitmq",
+			Proto:       "amqp",
+			Channel: &servicefile.Channel{
+				Name:     "emails",
+				Messages: []string{"email"},
+			},
+		},
+	}, sf.Relationships)
+}
+
+func TestImportErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		doc  Document
+	}{
+		{
+			name: "unsupported version",
+			doc:  Document{AsyncAPI: "1.2.0"},
+		},
+		{
+			name: "unsupported action",
+			doc: Document{
+				AsyncAPI:   "3.0.0",
+				Channels:   map[string]Channel{"a": {}},
+				Operations: map[string]Operation{"op": {Action: "publish", Channel: Reference{Ref: "#/channels/a"}}},
+			},
+		},
+		{
+			name: "missing channel",
+			doc: Document{
+				AsyncAPI:   "3.0.0",
+				Operations: map[string]Operation{"op": {Action: "send", Channel: Reference{Ref: "#/channels/a"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Import(&tt.doc)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadUnsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "asyncapi.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("asyncapi: 1.2.0\ninfo:\n  title: Legacy\n"), 0644))
+
+	_, err := Load(path)
+	assert.Error(t, err)
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	sf := &servicefile.ServiceFile{
+		Info: servicefile.Info{Name: "OrderService", Description: "Handles orders"},
+		Relationships: []servicefile.Relationship{
+			{
+				Action:      servicefile.RelationshipActionUses,
+				Participant: "PostgreSQL",
+				Technology:  "postgresql",
+			},
+			{
+				Action:      servicefile.RelationshipActionSends,
+				Participant: "Kafka",
+				Description: "Publishes order events",
+				Technology:  "kafka",
+				Proto:       "kafka",
+				Channel: &servicefile.Channel{
+					Name:     "orders.events",
+					Messages: []string{"OrderCreated", "OrderCancelled"},
+					Schema:   "#/components/schemas/OrderEvent",
+				},
+			},
+			{
+				Action:      servicefile.RelationshipActionReceives,
+				Participant: "RabbitMQ",
+				Technology:  "rabbitmq",
+				Proto:       "amqp",
+			},
+		},
+	}
+
+	doc := Export(sf)
+
+	assert.Equal(t, ExportVersion, doc.AsyncAPI)
+	assert.Equal(t, "OrderService", doc.Info.Title)
+
+	assert.Equal(t, map[string]Server{
+		"kafka":    {Host: "kafka", Protocol: "kafka"},
+		"rabbitmq": {Host: "rabbitmq", Protocol: "amqp"},
+	}, doc.Servers)
+
+	require.Contains(t, doc.Channels, "orders.events")
+	ch := doc.Channels["orders.events"]
+	assert.Equal(t, "orders.events", ch.Address)
+	assert.Equal(t, []ServerReference{{Name: "kafka", Ref: "#/servers/kafka"}}, ch.Servers)
+	assert.Equal(t, Message{Name: "OrderCreated", Payload: map[string]any{"$ref": "#/components/schemas/OrderEvent"}}, ch.Messages["OrderCreated"])
+	assert.Contains(t, ch.Bindings, "kafka")
+
+	require.Contains(t, doc.Channels, "RabbitMQ")
+	assert.Contains(t, doc.Channels["RabbitMQ"].Bindings, "amqp")
+
+	assert.Equal(t, Operation{
+		Action:      "send",
+		Channel:     Reference{Ref: "#/channels/orders.events"},
+		Description: "Publishes order events",
+		Messages: []Reference{
+			{Ref: "#/channels/orders.events/messages/OrderCreated"},
+			{Ref: "#/channels/orders.events/messages/OrderCancelled"},
+		},
+	}, doc.Operations["sendOrdersEvents"])
+	assert.Equal(t, "receive", doc.Operations["receiveRabbitMQ"].Action)
+	assert.Len(t, doc.Operations, 2)
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	sf := &servicefile.ServiceFile{
+		Info: servicefile.Info{Name: "OrderService"},
+		Relationships: []servicefile.Relationship{
+			{
+				Action:      servicefile.RelationshipActionSends,
+				Participant: "orders.events",
+				Technology:  "kafka",
+				Proto:       "kafka",
+				Channel: &servicefile.Channel{
+					Name:     "orders.events",
+					Messages: []string{"OrderCreated"},
+					Schema:   "#/components/schemas/Order",
+				},
+			},
+		},
+	}
+
+	data, err := yaml.Marshal(Export(sf))
+	require.NoError(t, err)
+
+	var doc Document
+	require.NoError(t, yaml.Unmarshal(data, &doc))
+
+	imported, err := Import(&doc)
+	require.NoError(t, err)
+
+	assert.Equal(t, sf.Relationships, imported.Relationships)
+}
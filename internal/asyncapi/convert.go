@@ -0,0 +1,442 @@
+package asyncapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+// ExportVersion is the AsyncAPI version of exported documents.
+const ExportVersion = "3.0.0"
+
+// bindingTechnologies maps AsyncAPI binding names to technologies.
+var bindingTechnologies = map[string]string{
+	"amqp":         "rabbitmq",
+	"amqp1":        "amqp",
+	"anypointmq":   "anypointmq",
+	"googlepubsub": "google-pubsub",
+	"http":         "http",
+	"ibmmq":        "ibmmq",
+	"jms":          "jms",
+	"kafka":        "kafka",
+	"mqtt":         "mqtt",
+	"nats":         "nats",
+	"pulsar":       "pulsar",
+	"redis":        "redis",
+	"sns":          "sns",
+	"solace":       "solace",
+	"sqs":          "sqs",
+	"ws":           "websocket",
+}
+
+// protocolBindings maps secure protocol variants to binding names.
+var protocolBindings = map[string]string{
+	"amqps":        "amqp",
+	"kafka-secure": "kafka",
+	"secure-mqtt":  "mqtt",
+	"https":        "http",
+	"wss":          "ws",
+}
+
+// Import converts the channels of an AsyncAPI document into sends and receives relationships.
+func Import(doc *Document) (*servicefile.ServiceFile, error) {
+	sf := &servicefile.ServiceFile{
+		Version: servicefile.Version,
+		Info: servicefile.Info{
+			Name:        doc.Info.Title,
+			Description: doc.Info.Description,
+		},
+		Relationships: []servicefile.Relationship{},
+	}
+
+	var (
+		rels []servicefile.Relationship
+		err  error
+	)
+
+	switch doc.MajorVersion() {
+	case "2":
+		rels = doc.importV2()
+	case "3":
+		rels, err = doc.importV3()
+	default:
+		return nil, fmt.Errorf("unsupported AsyncAPI version %q", doc.AsyncAPI)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	sf.Relationships = append(sf.Relationships, rels...)
+	sf.Sort()
+
+	return sf, nil
+}
+
+// importV2 imports AsyncAPI 2.x channels. In AsyncAPI 2.x subscribe describes the messages
+// the application sends and publish the messages the application receives.
+func (d *Document) importV2() []servicefile.Relationship {
+	var rels []servicefile.Relationship
+
+	for _, name := range sortedKeys(d.Channels) {
+		ch := d.Channels[name]
+
+		operations := []struct {
+			op     *ChannelOperation
+			action servicefile.RelationshipAction
+		}{
+			{op: ch.Subscribe, action: servicefile.RelationshipActionSends},
+			{op: ch.Publish, action: servicefile.RelationshipActionReceives},
+		}
+
+		for _, o := range operations {
+			if o.op == nil {
+				continue
+			}
+
+			var messages []Message
+			if o.op.Message != nil {
+				messages = d.flattenMessages([]Message{*o.op.Message})
+			}
+
+			rels = append(rels, d.relationship(o.action, name, ch, firstNonEmpty(o.op.Description, o.op.Summary), messages, o.op.Bindings))
+		}
+	}
+
+	return rels
+}
+
+// importV3 imports AsyncAPI 3.x operations.
+func (d *Document) importV3() ([]servicefile.Relationship, error) {
+	var rels []servicefile.Relationship
+
+	for _, id := range sortedKeys(d.Operations) {
+		op := d.Operations[id]
+
+		var action servicefile.RelationshipAction
+
+		switch op.Action {
+		case "send":
+			action = servicefile.RelationshipActionSends
+		case "receive":
+			action = servicefile.RelationshipActionReceives
+		default:
+			return nil, fmt.Errorf("operation %s: unsupported action %q", id, op.Action)
+		}
+
+		parts := refSegments(op.Channel.Ref)
+		if len(parts) != 2 || parts[0] != "channels" {
+			return nil, fmt.Errorf("operation %s: unsupported channel reference %q", id, op.Channel.Ref)
+		}
+
+		ch, ok := d.Channels[parts[1]]
+		if !ok {
+			return nil, fmt.Errorf("operation %s: channel %q not found", id, parts[1])
+		}
+
+		var messages []Message
+		if len(op.Messages) > 0 {
+			for _, ref := range op.Messages {
+				messages = append(messages, Message{Ref: ref.Ref})
+			}
+		} else {
+			for _, name := range sortedKeys(ch.Messages) {
+				m := ch.Messages[name]
+				if m.Name == "" && m.Ref == "" {
+					m.Name = name
+				}
+				messages = append(messages, m)
+			}
+		}
+
+		address := firstNonEmpty(ch.Address, parts[1])
+		description := firstNonEmpty(op.Description, op.Summary, ch.Description)
+
+		rels = append(rels, d.relationship(action, address, ch, description, d.flattenMessages(messages), op.Bindings))
+	}
+
+	return rels, nil
+}
+
+func (d *Document) relationship(
+	action servicefile.RelationshipAction,
+	address string,
+	ch Channel,
+	description string,
+	messages []Message,
+	opBindings map[string]any,
+) servicefile.Relationship {
+	proto := d.protocol(ch)
+
+	channel := &servicefile.Channel{Name: address}
+
+	for _, m := range messages {
+		if m.Name != "" {
+			channel.Messages = append(channel.Messages, m.Name)
+		}
+	}
+
+	if len(messages) == 1 {
+		if ref, ok := messages[0].Payload["$ref"].(string); ok {
+			channel.Schema = ref
+		}
+	}
+
+	return servicefile.Relationship{
+		Action:      action,
+		Participant: address,
+		Description: description,
+		Technology:  technology(proto, ch.Bindings, opBindings),
+		Proto:       proto,
+		Channel:     channel,
+	}
+}
+
+// flattenMessages resolves references and expands oneOf lists.
+func (d *Document) flattenMessages(messages []Message) []Message {
+	var result []Message
+
+	for _, m := range messages {
+		m = d.resolveMessage(m)
+
+		if len(m.OneOf) > 0 {
+			result = append(result, d.flattenMessages(m.OneOf)...)
+			continue
+		}
+
+		result = append(result, m)
+	}
+
+	return result
+}
+
+// protocol returns the protocol of the servers the channel is available on.
+func (d *Document) protocol(ch Channel) string {
+	names := make([]string, 0, len(ch.Servers))
+	for _, s := range ch.Servers {
+		names = append(names, s.Name)
+	}
+
+	if len(names) == 0 {
+		names = sortedKeys(d.Servers)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if server, ok := d.Servers[name]; ok && server.Protocol != "" {
+			return server.Protocol
+		}
+	}
+
+	return ""
+}
+
+// technology returns the technology of a channel from its bindings, falling back to the protocol.
+func technology(proto string, bindings ...map[string]any) string {
+	for _, b := range bindings {
+		for _, name := range sortedKeys(b) {
+			if t, ok := bindingTechnologies[name]; ok {
+				return t
+			}
+		}
+	}
+
+	proto = strings.ToLower(proto)
+	if binding, ok := protocolBindings[proto]; ok {
+		proto = binding
+	}
+
+	if t, ok := bindingTechnologies[proto]; ok {
+		return t
+	}
+
+	return proto
+}
+
+// Export converts the sends and receives relationships of a service file into a skeleton
+// AsyncAPI 3.x document.
+func Export(sf *servicefile.ServiceFile) *Document {
+	doc := &Document{
+		AsyncAPI: ExportVersion,
+		Info: Info{
+			Title:       sf.Info.Name,
+			Version:     "1.0.0",
+			Description: sf.Info.Description,
+		},
+		Servers:    make(map[string]Server),
+		Channels:   make(map[string]Channel),
+		Operations: make(map[string]Operation),
+	}
+
+	for _, rel := range sf.Relationships {
+		if !rel.Action.IsAsync() {
+			continue
+		}
+
+		address := rel.Participant
+		if rel.Channel != nil && rel.Channel.Name != "" {
+			address = rel.Channel.Name
+		}
+
+		if address == "" {
+			address = string(rel.Action)
+		}
+
+		channelID := identifier(address)
+
+		ch, ok := doc.Channels[channelID]
+		if !ok {
+			ch = Channel{Address: address}
+		}
+
+		if serverID, server, ok := exportServer(rel); ok {
+			doc.Servers[serverID] = server
+
+			if !hasServer(ch.Servers, serverID) {
+				ch.Servers = append(ch.Servers, ServerReference{Name: serverID, Ref: "#/servers/" + escapeRefSegment(serverID)})
+			}
+		}
+
+		if binding := exportBinding(rel.Technology); binding != "" {
+			if ch.Bindings == nil {
+				ch.Bindings = make(map[string]any)
+			}
+
+			ch.Bindings[binding] = map[string]any{}
+		}
+
+		op := Operation{
+			Action:      "send",
+			Channel:     Reference{Ref: "#/channels/" + escapeRefSegment(channelID)},
+			Description: rel.Description,
+		}
+
+		if rel.Action == servicefile.RelationshipActionReceives {
+			op.Action = "receive"
+		}
+
+		if rel.Channel != nil {
+			for _, name := range rel.Channel.Messages {
+				messageID := identifier(name)
+
+				if ch.Messages == nil {
+					ch.Messages = make(map[string]Message)
+				}
+
+				message := Message{Name: name}
+				if rel.Channel.Schema != "" {
+					message.Payload = map[string]any{"$ref": rel.Channel.Schema}
+				}
+
+				ch.Messages[messageID] = message
+
+				op.Messages = append(op.Messages, Reference{
+					Ref: "#/channels/" + escapeRefSegment(channelID) + "/messages/" + escapeRefSegment(messageID),
+				})
+			}
+		}
+
+		doc.Channels[channelID] = ch
+		doc.Operations[uniqueKey(doc.Operations, op.Action+upperFirst(channelID))] = op
+	}
+
+	return doc
+}
+
+func exportServer(rel servicefile.Relationship) (string, Server, bool) {
+	host := firstNonEmpty(rel.Technology, rel.Participant)
+	protocol := firstNonEmpty(rel.Proto, rel.Technology)
+
+	if host == "" || protocol == "" {
+		return "", Server{}, false
+	}
+
+	return identifier(strings.ToLower(host)), Server{Host: strings.ToLower(host), Protocol: protocol}, true
+}
+
+func exportBinding(technology string) string {
+	for binding, t := range bindingTechnologies {
+		if t == technology {
+			return binding
+		}
+	}
+
+	return ""
+}
+
+func hasServer(servers []ServerReference, name string) bool {
+	for _, s := range servers {
+		if s.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+var nonIdentifier = regexp.MustCompile(`[^A-Za-z0-9_.\-]+`)
+
+// identifier turns a name into a valid AsyncAPI component identifier.
+func identifier(name string) string {
+	return strings.Trim(nonIdentifier.ReplaceAllString(name, "_"), "_")
+}
+
+func uniqueKey[T any](m map[string]T, key string) string {
+	if _, ok := m[key]; !ok {
+		return key
+	}
+
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s%d", key, i)
+		if _, ok := m[candidate]; !ok {
+			return candidate
+		}
+	}
+}
+
+func upperFirst(s string) string {
+	var b strings.Builder
+
+	upper := true
+	for _, r := range s {
+		if r == '.' || r == '-' || r == '_' {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,131 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFiles are the compose files looked up in the working directory, in order of preference.
+var DefaultFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// File represents a compose file. Only the parts describing services and their
+// dependencies are modelled.
+type File struct {
+	Name     string             `yaml:"name,omitempty"`
+	Services map[string]Service `yaml:"services"`
+}
+
+// Service represents a service of a compose file.
+type Service struct {
+	Image string `yaml:"image,omitempty"`
+	// Build is set for services built from source, either a context path or a build section.
+	Build     yaml.Node `yaml:"build,omitempty"`
+	DependsOn Names     `yaml:"depends_on,omitempty"`
+	Links     []string  `yaml:"links,omitempty"`
+}
+
+// Built reports whether the service is built from source rather than run from an image.
+func (s Service) Built() bool {
+	return s.Build.Kind != 0
+}
+
+// Dependencies returns the names of the services the service depends on or links to, in order.
+func (s Service) Dependencies() []string {
+	var (
+		names []string
+		seen  = make(map[string]bool)
+	)
+
+	for _, name := range s.DependsOn {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, link := range s.Links {
+		// A link is either "service" or "service:alias".
+		name, _, _ := strings.Cut(link, ":")
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// Names holds service names written either as a list or as the keys of a map,
+// e.g. the short and long syntax of depends_on.
+type Names []string
+
+// UnmarshalYAML decodes a list of names or a map keyed by names.
+func (n *Names) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.SequenceNode:
+		var names []string
+		if err := value.Decode(&names); err != nil {
+			return err
+		}
+
+		*n = names
+	case yaml.MappingNode:
+		names := make([]string, 0, len(value.Content)/2)
+		for i := 0; i < len(value.Content); i += 2 {
+			names = append(names, value.Content[i].Value)
+		}
+
+		*n = names
+	default:
+		return fmt.Errorf("line %d: expected a list or a map of service names", value.Line)
+	}
+
+	return nil
+}
+
+// Load reads and parses compose files. Services of later files override the ones of
+// earlier files with the same name, the same way as docker compose -f a.yaml -f b.yaml:
+// fields are replaced and dependencies are added.
+func Load(paths ...string) (*File, error) {
+	result := &File{Services: make(map[string]Service)}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+
+		var f File
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to parse file %s: %w", path, err)
+		}
+
+		if f.Name != "" {
+			result.Name = f.Name
+		}
+
+		for name, s := range f.Services {
+			result.Services[name] = merge(result.Services[name], s)
+		}
+	}
+
+	return result, nil
+}
+
+func merge(base, override Service) Service {
+	if override.Image != "" {
+		base.Image = override.Image
+	}
+
+	if override.Built() {
+		base.Build = override.Build
+	}
+
+	base.DependsOn = append(base.DependsOn, override.DependsOn...)
+	base.Links = append(base.Links, override.Links...)
+
+	return base
+}
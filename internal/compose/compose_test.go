@@ -0,0 +1,88 @@
+package compose
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	f, err := Load(filepath.Join("testdata", "docker-compose.yml"), filepath.Join("testdata", "docker-compose.override.yml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "shop", f.Name)
+	assert.Len(t, f.Services, 6)
+	assert.True(t, f.Services["api"].Built())
+	assert.True(t, f.Services["worker"].Built())
+	assert.False(t, f.Services["postgres"].Built())
+	assert.Equal(t, []string{"postgres", "redis", "mailhog"}, f.Services["api"].Dependencies())
+	assert.Equal(t, []string{"postgres", "kafka", "api"}, f.Services["worker"].Dependencies())
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Load(filepath.Join("testdata", "missing.yml"))
+	assert.Error(t, err)
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	f, err := Load(filepath.Join("testdata", "docker-compose.yml"), filepath.Join("testdata", "docker-compose.override.yml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []*servicefile.ServiceFile{
+		{
+			Version: servicefile.Version,
+			Info:    servicefile.Info{Name: "api", System: "shop"},
+			Relationships: []servicefile.Relationship{
+				{Action: servicefile.RelationshipActionUses, Participant: "mailhog"},
+				{Action: servicefile.RelationshipActionUses, Participant: "postgres", Technology: "postgresql"},
+				{Action: servicefile.RelationshipActionUses, Participant: "redis", Technology: "redis"},
+			},
+		},
+		{
+			Version: servicefile.Version,
+			Info:    servicefile.Info{Name: "worker", System: "shop"},
+			Relationships: []servicefile.Relationship{
+				{Action: servicefile.RelationshipActionUses, Participant: "api"},
+				{Action: servicefile.RelationshipActionUses, Participant: "kafka", Technology: "kafka"},
+				{Action: servicefile.RelationshipActionUses, Participant: "postgres", Technology: "postgresql"},
+			},
+		},
+	}, Import(f))
+}
+
+func TestTechnology(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{image: "postgres", expected: "postgresql"},
+		{image: "bitnami/postgresql:16", expected: "postgresql"},
+		{image: "postgis/postgis:16-3.4", expected: "postgresql"},
+		{image: "docker.io/library/redis:7@sha256:abc", expected: "redis"},
+		{image: "rabbitmq:3-management", expected: "rabbitmq"},
+		{image: "confluentinc/cp-kafka:7.6.0", expected: "kafka"},
+		{image: "mongo:7", expected: "mongodb"},
+		{image: "registry.example.com:5000/team/api:1.0", expected: ""},
+		{image: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, Technology(tt.image))
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package compose
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+// imageTechnologies maps image names to technologies. An image matches the first entry
+// its name, without registry, namespace and tag, contains.
+var imageTechnologies = []struct {
+	name       string
+	technology string
+}{
+	{"postgis", "postgresql"},
+	{"postgres", "postgresql"},
+	{"mysql", "mysql"},
+	{"mariadb", "mariadb"},
+	{"mongo", "mongodb"},
+	{"redis", "redis"},
+	{"valkey", "redis"},
+	{"memcached", "memcached"},
+	{"rabbitmq", "rabbitmq"},
+	{"kafka", "kafka"},
+	{"redpanda", "kafka"},
+	{"zookeeper", "zookeeper"},
+	{"nats", "nats"},
+	{"elasticsearch", "elasticsearch"},
+	{"opensearch", "opensearch"},
+	{"cassandra", "cassandra"},
+	{"clickhouse", "clickhouse"},
+	{"minio", "s3"},
+	{"localstack", "aws"},
+}
+
+// Technology guesses the technology of an image, e.g. postgresql for bitnami/postgresql:16.
+// It returns an empty string for unknown images.
+func Technology(image string) string {
+	name := image
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	name, _, _ = strings.Cut(name, "@")
+	name, _, _ = strings.Cut(name, ":")
+	name = strings.ToLower(name)
+
+	for _, it := range imageTechnologies {
+		if strings.Contains(name, it.name) {
+			return it.technology
+		}
+	}
+
+	return ""
+}
+
+// Import creates draft service files for the services built from source, with uses
+// relationships for the services they depend on or link to. Service files are sorted by name.
+func Import(f *File) []*servicefile.ServiceFile {
+	names := make([]string, 0, len(f.Services))
+	for name, s := range f.Services {
+		if s.Built() {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	serviceFiles := make([]*servicefile.ServiceFile, 0, len(names))
+
+	for _, name := range names {
+		sf := &servicefile.ServiceFile{
+			Version: servicefile.Version,
+			Info: servicefile.Info{
+				Name:   name,
+				System: f.Name,
+			},
+			Relationships: []servicefile.Relationship{},
+		}
+
+		for _, dep := range f.Services[name].Dependencies() {
+			sf.Relationships = append(sf.Relationships, servicefile.Relationship{
+				Action:      servicefile.RelationshipActionUses,
+				Participant: dep,
+				Technology:  Technology(f.Services[dep].Image),
+			})
+		}
+
+		sf.Sort()
+		serviceFiles = append(serviceFiles, sf)
+	}
+
+	return serviceFiles
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+	"gopkg.in/yaml.v3"
+)
+
+// File is the name of the project configuration file.
+const File = ".servicefile.yaml"
+
+// Config represents the project configuration of the CLI.
+type Config struct {
+	// Dir is the directory to parse.
+	Dir       string `yaml:"dir"`
+	Recursive bool   `yaml:"recursive"`
+	// Output is the output file path, suffixed with the lowercased service name when there are
+	// several services, or a template like docs/{{ lower .Name }}.yaml executed with the info
+	// of every service.
+	Output           string   `yaml:"output"`
+	DetectRepository bool     `yaml:"detect-repository"`
+	Include          []string `yaml:"include,omitempty"`
+	Exclude          []string `yaml:"exclude,omitempty"`
+	// Languages are the languages to parse, all supported languages if empty.
+	Languages []string `yaml:"languages,omitempty"`
+	// Defaults are set on the services that don't declare them.
+	Defaults Defaults `yaml:"defaults,omitempty"`
+	// Inference lists the rules completing the relationships, applied in order.
+	Inference []Rule `yaml:"inference,omitempty"`
+	// Strict fails on unknown annotation fields and relationship actions.
+	Strict           bool   `yaml:"strict"`
+	ExtensionsSchema string `yaml:"extensions-schema,omitempty"`
+	Overlay          string `yaml:"overlay,omitempty"`
+}
+
+// Defaults represents the info fields set on the services that don't declare them.
+type Defaults struct {
+	Owner  string `yaml:"owner,omitempty"`
+	System string `yaml:"system,omitempty"`
+}
+
+// Rule completes the relationships whose participant matches the regular expression,
+// e.g. technology postgresql and proto tcp for (?i)^postgres. Only the fields the
+// relationships don't set are filled.
+type Rule struct {
+	Participant string `yaml:"participant"`
+	Technology  string `yaml:"technology,omitempty"`
+	Proto       string `yaml:"proto,omitempty"`
+	External    bool   `yaml:"external,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// Default returns the configuration used without a configuration file, matching the
+// defaults of the flags.
+func Default() *Config {
+	return &Config{
+		Dir:              ".",
+		Recursive:        true,
+		Output:           "servicefile.yaml",
+		DetectRepository: true,
+	}
+}
+
+// Find returns the path of the configuration file of dir or of its closest parent, stopping
+// at the root of the git repository. It returns an empty path if there is none.
+func Find(dir string) (string, error) {
+	start, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for current := start; ; {
+		path := filepath.Join(current, File)
+		if _, err := os.Stat(path); err == nil {
+			// Keep the path relative to dir, so are the paths of the configuration.
+			if rel, err := filepath.Rel(start, path); err == nil {
+				path = filepath.Join(dir, rel)
+			}
+
+			return path, nil
+		}
+
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return "", nil
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", nil
+		}
+
+		current = parent
+	}
+}
+
+// Load reads the configuration file at the given path over the defaults. Relative paths of
+// the configuration are relative to its directory.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	cfg := Default()
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse file %s: %w", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration %s: %w", path, err)
+	}
+
+	base := filepath.Dir(path)
+	for _, p := range []*string{&cfg.Dir, &cfg.Output, &cfg.ExtensionsSchema, &cfg.Overlay} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(base, *p)
+		}
+	}
+
+	return cfg, nil
+}
+
+// Validate checks the regular expressions of the inference rules.
+func (c *Config) Validate() error {
+	for i := range c.Inference {
+		rule := &c.Inference[i]
+
+		re, err := regexp.Compile(rule.Participant)
+		if err != nil {
+			return fmt.Errorf("inference rule %d: invalid participant: %w", i+1, err)
+		}
+
+		rule.re = re
+	}
+
+	return nil
+}
+
+// Apply sets the defaults on the services and completes their relationships with the
+// inference rules. The configuration must be validated.
+func (c *Config) Apply(serviceFiles []*servicefile.ServiceFile) {
+	for _, sf := range serviceFiles {
+		if sf.Info.Owner == "" {
+			sf.Info.Owner = c.Defaults.Owner
+		}
+
+		if sf.Info.System == "" {
+			sf.Info.System = c.Defaults.System
+		}
+
+		for i := range sf.Relationships {
+			rel := &sf.Relationships[i]
+
+			for _, rule := range c.Inference {
+				if rule.re == nil || !rule.re.MatchString(rel.Participant) {
+					continue
+				}
+
+				if rel.Technology == "" {
+					rel.Technology = rule.Technology
+				}
+
+				if rel.Proto == "" {
+					rel.Proto = rule.Proto
+				}
+
+				rel.External = rel.External || rule.External
+			}
+		}
+	}
+}
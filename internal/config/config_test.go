@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, File)
+	writeFile(t, path, `dir: services
+output: docs/{{ lower .Name }}.yaml
+exclude: [vendor]
+languages: [go, python]
+defaults:
+  owner: platform
+inference:
+  - participant: (?i)^postgres
+    technology: postgresql
+strict: true
+`)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(dir, "services"), cfg.Dir)
+	assert.Equal(t, filepath.Join(dir, "docs/{{ lower .Name }}.yaml"), cfg.Output)
+	assert.True(t, cfg.Recursive)
+	assert.True(t, cfg.DetectRepository)
+	assert.True(t, cfg.Strict)
+	assert.Equal(t, []string{"vendor"}, cfg.Exclude)
+	assert.Equal(t, []string{"go", "python"}, cfg.Languages)
+	assert.Equal(t, Defaults{Owner: "platform"}, cfg.Defaults)
+
+	writeFile(t, path, "recursiv: false\n")
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "field recursiv not found")
+
+	writeFile(t, path, "inference:\n  - participant: '('\n")
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "inference rule 1: invalid participant")
+
+	writeFile(t, path, "")
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, dir, cfg.Dir)
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, File), "")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "services", "orders"), 0755))
+
+	path, err := Find(filepath.Join(repo, "services", "orders"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, "services", "orders", "..", "..", File), path)
+
+	// The search stops at the root of the git repository.
+	nested := filepath.Join(repo, "vendor", "lib")
+	require.NoError(t, os.MkdirAll(filepath.Join(nested, ".git"), 0755))
+
+	path, err = Find(nested)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	cfg := Default()
+	cfg.Defaults = Defaults{Owner: "platform", System: "shop"}
+	cfg.Inference = []Rule{
+		{Participant: "(?i)^postgres", Technology: "postgresql", Proto: "tcp"},
+		{Participant: "^Stripe$", Technology: "http", External: true},
+	}
+	require.NoError(t, cfg.Validate())
+
+	sf := &servicefile.ServiceFile{
+		Info: servicefile.Info{Name: "orders", Owner: "team-orders"},
+		Relationships: []servicefile.Relationship{
+			{Action: servicefile.RelationshipActionUses, Participant: "PostgreSQL", Proto: "unix"},
+			{Action: servicefile.RelationshipActionRequests, Participant: "Stripe"},
+			{Action: servicefile.RelationshipActionUses, Participant: "Redis"},
+		},
+	}
+
+	cfg.Apply([]*servicefile.ServiceFile{sf})
+
+	assert.Equal(t, servicefile.Info{Name: "orders", Owner: "team-orders", System: "shop"}, sf.Info)
+	assert.Equal(t, []servicefile.Relationship{
+		{Action: servicefile.RelationshipActionUses, Participant: "PostgreSQL", Technology: "postgresql", Proto: "unix"},
+		{Action: servicefile.RelationshipActionRequests, Participant: "Stripe", Technology: "http", External: true},
+		{Action: servicefile.RelationshipActionUses, Participant: "Redis"},
+	}, sf.Relationships)
+}
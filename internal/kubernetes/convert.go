@@ -0,0 +1,340 @@
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+const (
+	// PartOfLabel is the label the system of a service is read from.
+	PartOfLabel = "app.kubernetes.io/part-of"
+	// DescriptionAnnotation is the annotation the description of a service is read from.
+	DescriptionAnnotation = "kubernetes.io/description"
+)
+
+// DefaultOwnerAnnotations are the annotations the owner of a service is read from, in order.
+var DefaultOwnerAnnotations = []string{"owner", "team"}
+
+// Inference explains a value the importer inferred from the manifests.
+type Inference struct {
+	Service string
+	// Field is the field of the service file that was set, e.g. system or requests orders.
+	Field  string
+	Value  string
+	Source string
+}
+
+func (i Inference) String() string {
+	if i.Value == "" {
+		return fmt.Sprintf("%s: %s from %s", i.Service, i.Field, i.Source)
+	}
+
+	return fmt.Sprintf("%s: %s %q from %s", i.Service, i.Field, i.Value, i.Source)
+}
+
+// Import creates a service file for every workload of the manifests, along with the
+// inferences it is built from. Values of environment variables and ConfigMaps referencing
+// the DNS name of a Service become requests relationships to the workload the Service
+// selects, or to the Service itself if it selects no workload.
+func Import(m *Manifests, ownerAnnotations []string) ([]*servicefile.ServiceFile, []Inference) {
+	var (
+		serviceFiles []*servicefile.ServiceFile
+		inferences   []Inference
+		hosts        = m.hosts()
+	)
+
+	for _, w := range m.Workloads {
+		sf := &servicefile.ServiceFile{
+			Version: servicefile.Version,
+			Info: servicefile.Info{
+				Name: w.Metadata.Name,
+			},
+			Relationships: []servicefile.Relationship{},
+		}
+
+		infer := func(field, value, source string) {
+			inferences = append(inferences, Inference{Service: sf.Info.Name, Field: field, Value: value, Source: source})
+		}
+
+		if value, source := w.label(PartOfLabel); value != "" {
+			sf.Info.System = value
+			infer("system", value, source)
+		}
+
+		if value, source := w.annotation(DescriptionAnnotation); value != "" {
+			sf.Info.Description = value
+			infer("description", value, source)
+		}
+
+		for _, key := range ownerAnnotations {
+			if value, source := w.annotation(key); value != "" {
+				sf.Info.Owner = value
+				infer("owner", value, source)
+
+				break
+			}
+		}
+
+		requested := make(map[string]bool)
+
+		for _, v := range m.values(w) {
+			for _, ref := range references(v.value) {
+				participant, ok := hosts.resolve(ref, w.Metadata.namespace())
+				if !ok || participant == sf.Info.Name || requested[participant] {
+					continue
+				}
+
+				requested[participant] = true
+
+				sf.Relationships = append(sf.Relationships, servicefile.Relationship{
+					Action:      servicefile.RelationshipActionRequests,
+					Participant: participant,
+					Proto:       ref.proto(),
+				})
+
+				infer(servicefile.RelationshipActionRequests+" "+participant, ref.host, v.source)
+			}
+		}
+
+		sf.Sort()
+		serviceFiles = append(serviceFiles, sf)
+	}
+
+	return serviceFiles, inferences
+}
+
+// label returns the value of a label of the workload or of its pod template.
+func (w Workload) label(key string) (string, string) {
+	if value := w.Metadata.Labels[key]; value != "" {
+		return value, fmt.Sprintf("label %s of %s", key, w.describe())
+	}
+
+	if value := w.Spec.Template.Metadata.Labels[key]; value != "" {
+		return value, fmt.Sprintf("pod template label %s of %s", key, w.describe())
+	}
+
+	return "", ""
+}
+
+// annotation returns the value of an annotation of the workload or of its pod template.
+func (w Workload) annotation(key string) (string, string) {
+	if value := w.Metadata.Annotations[key]; value != "" {
+		return value, fmt.Sprintf("annotation %s of %s", key, w.describe())
+	}
+
+	if value := w.Spec.Template.Metadata.Annotations[key]; value != "" {
+		return value, fmt.Sprintf("pod template annotation %s of %s", key, w.describe())
+	}
+
+	return "", ""
+}
+
+func (w Workload) describe() string {
+	return fmt.Sprintf("%s %s/%s (%s)", w.Kind, w.Metadata.namespace(), w.Metadata.Name, w.Path)
+}
+
+// selects reports whether the Service selects the pods of the workload.
+func (s Service) selects(w Workload) bool {
+	if len(s.Spec.Selector) == 0 || s.Metadata.namespace() != w.Metadata.namespace() {
+		return false
+	}
+
+	for key, value := range s.Spec.Selector {
+		if w.Spec.Template.Metadata.Labels[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hostTable maps the DNS names of Services to participants. Short names, which only
+// resolve within the namespace of the Service, are keyed by namespace/name.
+type hostTable map[string]string
+
+func (m *Manifests) hosts() hostTable {
+	hosts := make(hostTable)
+
+	for _, s := range m.Services {
+		participant := s.Metadata.Name
+
+		for _, w := range m.Workloads {
+			if s.selects(w) {
+				participant = w.Metadata.Name
+				break
+			}
+		}
+
+		name, ns := s.Metadata.Name, s.Metadata.namespace()
+
+		hosts[ns+"/"+name] = participant
+		hosts[name+"."+ns] = participant
+		hosts[name+"."+ns+".svc"] = participant
+		hosts[name+"."+ns+".svc.cluster.local"] = participant
+	}
+
+	return hosts
+}
+
+// resolve returns the participant a reference found in the given namespace points to.
+// Short names are only resolved when they can't be mistaken for plain words.
+func (h hostTable) resolve(ref reference, namespace string) (string, bool) {
+	if participant, ok := h[ref.host]; ok {
+		return participant, true
+	}
+
+	if ref.scheme == "" && !ref.port && !ref.whole {
+		return "", false
+	}
+
+	participant, ok := h[namespace+"/"+ref.host]
+
+	return participant, ok
+}
+
+// configValue is a value of the configuration of a workload along with where it was found.
+type configValue struct {
+	value  string
+	source string
+}
+
+// values returns the environment variables of the containers of the workload and the data
+// of the ConfigMaps they reference.
+func (m *Manifests) values(w Workload) []configValue {
+	var values []configValue
+
+	namespace := w.Metadata.namespace()
+
+	configMap := func(name string) (ConfigMap, bool) {
+		for _, c := range m.ConfigMaps {
+			if c.Metadata.Name == name && c.Metadata.namespace() == namespace {
+				return c, true
+			}
+		}
+
+		return ConfigMap{}, false
+	}
+
+	configMapData := func(name string) {
+		c, ok := configMap(name)
+		if !ok {
+			return
+		}
+
+		keys := make([]string, 0, len(c.Data))
+		for key := range c.Data {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			values = append(values, configValue{
+				value:  c.Data[key],
+				source: fmt.Sprintf("key %s of ConfigMap %s/%s", key, namespace, name),
+			})
+		}
+	}
+
+	spec := w.Spec.Template.Spec
+
+	for _, c := range append(append([]Container{}, spec.InitContainers...), spec.Containers...) {
+		for _, env := range c.Env {
+			switch {
+			case env.Value != "":
+				values = append(values, configValue{
+					value:  env.Value,
+					source: fmt.Sprintf("env %s of container %s of %s", env.Name, c.Name, w.describe()),
+				})
+			case env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil:
+				ref := env.ValueFrom.ConfigMapKeyRef
+				if cm, ok := configMap(ref.Name); ok && cm.Data[ref.Key] != "" {
+					values = append(values, configValue{
+						value:  cm.Data[ref.Key],
+						source: fmt.Sprintf("key %s of ConfigMap %s/%s", ref.Key, namespace, ref.Name),
+					})
+				}
+			}
+		}
+
+		for _, envFrom := range c.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				configMapData(envFrom.ConfigMapRef.Name)
+			}
+		}
+	}
+
+	for _, v := range spec.Volumes {
+		if v.ConfigMap != nil {
+			configMapData(v.ConfigMap.Name)
+		}
+	}
+
+	return values
+}
+
+// reference is a host name found in a configuration value.
+type reference struct {
+	host   string
+	scheme string
+	// port is set when the host is followed by a port.
+	port bool
+	// whole is set when the host is the whole configuration value.
+	whole bool
+}
+
+func (r reference) proto() string {
+	switch r.scheme {
+	case "http", "https":
+		return "http"
+	case "grpc", "grpcs":
+		return "grpc"
+	default:
+		return ""
+	}
+}
+
+var hostPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// references returns the host names found in a configuration value, e.g. in URLs,
+// host:port pairs or configuration files.
+func references(value string) []reference {
+	tokens := strings.FieldsFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`,;="'()[]{}<>`, r)
+	})
+
+	var refs []reference
+
+	for _, token := range tokens {
+		ref := reference{whole: token == strings.TrimSpace(value)}
+
+		if scheme, rest, ok := strings.Cut(token, "://"); ok {
+			ref.scheme = strings.ToLower(scheme)
+			token = rest
+		}
+
+		token, _, _ = strings.Cut(token, "/")
+		token, _, _ = strings.Cut(token, "?")
+
+		if i := strings.LastIndexByte(token, '@'); i >= 0 {
+			token = token[i+1:]
+		}
+
+		if host, port, ok := strings.Cut(token, ":"); ok && port != "" && strings.Trim(port, "0123456789") == "" {
+			token = host
+			ref.port = true
+		}
+
+		ref.host = strings.TrimSuffix(strings.ToLower(token), ".")
+		if hostPattern.MatchString(ref.host) {
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
@@ -0,0 +1,220 @@
+package kubernetes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultNamespace is the namespace of objects that don't declare one.
+const DefaultNamespace = "default"
+
+// Manifests holds the objects of rendered manifests that describe services and their
+// dependencies: workloads, Services and ConfigMaps. Other objects are ignored.
+type Manifests struct {
+	Workloads  []Workload
+	Services   []Service
+	ConfigMaps []ConfigMap
+}
+
+// Metadata represents the metadata of an object.
+type Metadata struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// Workload represents a Deployment or a StatefulSet.
+type Workload struct {
+	Kind     string   `yaml:"kind"`
+	Metadata Metadata `yaml:"metadata"`
+	Spec     struct {
+		Template PodTemplate `yaml:"template"`
+	} `yaml:"spec"`
+	// Path is the file the workload was read from.
+	Path string `yaml:"-"`
+}
+
+// PodTemplate represents the pod template of a workload.
+type PodTemplate struct {
+	Metadata Metadata `yaml:"metadata"`
+	Spec     struct {
+		InitContainers []Container `yaml:"initContainers,omitempty"`
+		Containers     []Container `yaml:"containers"`
+		Volumes        []Volume    `yaml:"volumes,omitempty"`
+	} `yaml:"spec"`
+}
+
+// Container represents a container of a pod template.
+type Container struct {
+	Name    string    `yaml:"name"`
+	Env     []EnvVar  `yaml:"env,omitempty"`
+	EnvFrom []EnvFrom `yaml:"envFrom,omitempty"`
+}
+
+// EnvVar represents an environment variable of a container.
+type EnvVar struct {
+	Name      string `yaml:"name"`
+	Value     string `yaml:"value,omitempty"`
+	ValueFrom *struct {
+		ConfigMapKeyRef *struct {
+			Name string `yaml:"name"`
+			Key  string `yaml:"key"`
+		} `yaml:"configMapKeyRef,omitempty"`
+	} `yaml:"valueFrom,omitempty"`
+}
+
+// EnvFrom represents a source of environment variables of a container.
+type EnvFrom struct {
+	ConfigMapRef *struct {
+		Name string `yaml:"name"`
+	} `yaml:"configMapRef,omitempty"`
+}
+
+// Volume represents a volume of a pod template.
+type Volume struct {
+	ConfigMap *struct {
+		Name string `yaml:"name"`
+	} `yaml:"configMap,omitempty"`
+}
+
+// Service represents a Service, which gives the pods it selects a DNS name.
+type Service struct {
+	Metadata Metadata `yaml:"metadata"`
+	Spec     struct {
+		Selector map[string]string `yaml:"selector,omitempty"`
+	} `yaml:"spec"`
+}
+
+// ConfigMap represents a ConfigMap.
+type ConfigMap struct {
+	Metadata Metadata          `yaml:"metadata"`
+	Data     map[string]string `yaml:"data,omitempty"`
+}
+
+// namespace returns the namespace of the object, DefaultNamespace if it doesn't declare one.
+func (m Metadata) namespace() string {
+	if m.Namespace == "" {
+		return DefaultNamespace
+	}
+
+	return m.Namespace
+}
+
+// Load reads the manifests of the .yaml and .yml files found in dir and its subdirectories.
+// Files may hold several documents, e.g. the output of helm template, and List objects.
+func Load(dir string) (*Manifests, error) {
+	m := &Manifests{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || (filepath.Ext(path) != ".yaml" && filepath.Ext(path) != ".yml") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+
+		if err := m.parse(path, data); err != nil {
+			return fmt.Errorf("failed to parse file %s: %w", path, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(m.Workloads, func(i, j int) bool {
+		return m.Workloads[i].Metadata.Name < m.Workloads[j].Metadata.Name
+	})
+
+	return m, nil
+}
+
+func (m *Manifests) parse(path string, data []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		var doc yaml.Node
+
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if len(doc.Content) == 0 {
+			continue
+		}
+
+		if err := m.add(path, doc.Content[0]); err != nil {
+			return err
+		}
+	}
+}
+
+func (m *Manifests) add(path string, node *yaml.Node) error {
+	var header struct {
+		Kind  string      `yaml:"kind"`
+		Items []yaml.Node `yaml:"items"`
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	if err := node.Decode(&header); err != nil {
+		return err
+	}
+
+	switch {
+	case header.Kind == "Deployment" || header.Kind == "StatefulSet":
+		var w Workload
+		if err := node.Decode(&w); err != nil {
+			return err
+		}
+
+		w.Path = path
+		m.Workloads = append(m.Workloads, w)
+	case header.Kind == "Service":
+		var s Service
+		if err := node.Decode(&s); err != nil {
+			return err
+		}
+
+		m.Services = append(m.Services, s)
+	case header.Kind == "ConfigMap":
+		var c ConfigMap
+		if err := node.Decode(&c); err != nil {
+			return err
+		}
+
+		m.ConfigMaps = append(m.ConfigMaps, c)
+	case strings.HasSuffix(header.Kind, "List"):
+		for i := range header.Items {
+			if err := m.add(path, &header.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
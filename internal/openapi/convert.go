@@ -0,0 +1,72 @@
+package openapi
+
+import (
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+const (
+	// Technology is the technology of relationships describing an OpenAPI document.
+	Technology = "http-server"
+	// Proto is the protocol of relationships describing an OpenAPI document.
+	Proto = "http"
+	// ServersExtension is the extension field the server URLs of the document are written to.
+	ServersExtension = "x-servers"
+)
+
+// Import converts an OpenAPI document into a service file with a single replies relationship.
+func Import(doc *Document) *servicefile.ServiceFile {
+	rel := servicefile.Relationship{Action: servicefile.RelationshipActionReplies}
+	Enrich(&rel, doc)
+
+	return &servicefile.ServiceFile{
+		Version: servicefile.Version,
+		Info: servicefile.Info{
+			Name:        doc.Info.Title,
+			Description: doc.Info.Description,
+		},
+		Relationships: []servicefile.Relationship{rel},
+	}
+}
+
+// Enrich fills a replies relationship with the info, servers and paths of an OpenAPI document.
+// Fields set on the relationship take precedence, operations are merged.
+func Enrich(rel *servicefile.Relationship, doc *Document) {
+	if rel.Description == "" {
+		rel.Description = doc.Info.Description
+	}
+
+	if rel.Technology == "" {
+		rel.Technology = Technology
+	}
+
+	if rel.Proto == "" {
+		rel.Proto = Proto
+	}
+
+	known := make(map[string]bool, len(rel.Operations))
+	for _, op := range rel.Operations {
+		known[servicefile.NormalizeOperation(op)] = true
+	}
+
+	for _, op := range doc.Operations() {
+		if !known[op] {
+			rel.Operations = append(rel.Operations, op)
+			known[op] = true
+		}
+	}
+
+	if urls := doc.ServerURLs(); len(urls) > 0 {
+		if _, ok := rel.Extensions[ServersExtension]; !ok {
+			if rel.Extensions == nil {
+				rel.Extensions = make(servicefile.Extensions)
+			}
+
+			servers := make([]any, 0, len(urls))
+			for _, url := range urls {
+				servers = append(servers, url)
+			}
+
+			rel.Extensions[ServersExtension] = servers
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// methods lists the HTTP methods of path item operations in the order they are reported.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Document represents an OpenAPI 3.x or Swagger 2.0 document.
+// Only the parts describing the API surface are modelled.
+type Document struct {
+	OpenAPI string `yaml:"openapi,omitempty"`
+	// Swagger, Host, BasePath and Schemes are used by Swagger 2.0.
+	Swagger  string                          `yaml:"swagger,omitempty"`
+	Info     Info                            `yaml:"info"`
+	Servers  []Server                        `yaml:"servers,omitempty"`
+	Host     string                          `yaml:"host,omitempty"`
+	BasePath string                          `yaml:"basePath,omitempty"`
+	Schemes  []string                        `yaml:"schemes,omitempty"`
+	Paths    map[string]map[string]yaml.Node `yaml:"paths,omitempty"`
+}
+
+// Info represents the info of the API.
+type Info struct {
+	Title       string `yaml:"title"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// Server represents a server the API is available on.
+type Server struct {
+	URL         string `yaml:"url"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// Load reads and parses an OpenAPI document from a YAML or JSON file at the given path.
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	return Parse(path, data)
+}
+
+// Parse parses an OpenAPI document in YAML or JSON, path is used in error messages.
+func Parse(path string, data []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", path, err)
+	}
+
+	if !strings.HasPrefix(doc.OpenAPI, "3.") && doc.Swagger != "2.0" {
+		return nil, fmt.Errorf("unsupported OpenAPI document %s: expected openapi 3.x or swagger 2.0", path)
+	}
+
+	return &doc, nil
+}
+
+// Operations returns the operations of the document as "METHOD /path", sorted by path.
+func (d *Document) Operations() []string {
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	var operations []string
+
+	for _, path := range paths {
+		for _, method := range methods {
+			if _, ok := d.Paths[path][method]; ok {
+				operations = append(operations, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+
+	return operations
+}
+
+// ServerURLs returns the URLs of the servers the API is available on.
+func (d *Document) ServerURLs() []string {
+	var urls []string
+
+	for _, s := range d.Servers {
+		if s.URL != "" {
+			urls = append(urls, s.URL)
+		}
+	}
+
+	if d.Host == "" {
+		return urls
+	}
+
+	schemes := d.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+
+	for _, scheme := range schemes {
+		urls = append(urls, scheme+"://"+d.Host+d.BasePath)
+	}
+
+	return urls
+}
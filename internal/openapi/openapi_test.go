@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load(filepath.Join("testdata", "openapi.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Users API", doc.Info.Title)
+	assert.Equal(t, []string{"GET /users", "POST /users", "GET /users/{id}"}, doc.Operations())
+	assert.Equal(t, []string{"https://users.example.com/v1"}, doc.ServerURLs())
+}
+
+func TestLoadSwagger(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load(filepath.Join("testdata", "swagger.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"GET /items", "DELETE /items"}, doc.Operations())
+	assert.Equal(t, []string{"http://legacy.example.com/api", "https://legacy.example.com/api"}, doc.ServerURLs())
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Load(filepath.Join("testdata", "missing.yaml"))
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "asyncapi.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("asyncapi: 3.0.0\ninfo:\n  title: Events\n"), 0644))
+
+	_, err = Load(path)
+	assert.Error(t, err)
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load(filepath.Join("testdata", "openapi.yaml"))
+	require.NoError(t, err)
+
+	sf := Import(doc)
+
+	assert.Equal(t, servicefile.Info{Name: "Users API", Description: "Provides user management APIs"}, sf.Info)
+	assert.Equal(t, []servicefile.Relationship{
+		{
+			Action:      servicefile.RelationshipActionReplies,
+			Description: "Provides user management APIs",
+			Technology:  Technology,
+			Proto:       Proto,
+			Operations:  []string{"GET /users", "POST /users", "GET /users/{id}"},
+			Extensions:  servicefile.Extensions{ServersExtension: []any{"https://users.example.com/v1"}},
+		},
+	}, sf.Relationships)
+}
+
+func TestEnrich(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load(filepath.Join("testdata", "openapi.yaml"))
+	require.NoError(t, err)
+
+	rel := servicefile.Relationship{
+		Action:      servicefile.RelationshipActionReplies,
+		Description: "Public API",
+		Technology:  "grpc-gateway",
+		Operations:  []string{"get /users", "GET /health"},
+		Extensions:  servicefile.Extensions{ServersExtension: []any{"https://api.example.com"}},
+	}
+
+	Enrich(&rel, doc)
+
+	assert.Equal(t, "Public API", rel.Description)
+	assert.Equal(t, "grpc-gateway", rel.Technology)
+	assert.Equal(t, Proto, rel.Proto)
+	assert.Equal(t, []string{"get /users", "GET /health", "POST /users", "GET /users/{id}"}, rel.Operations)
+	assert.Equal(t, []any{"https://api.example.com"}, rel.Extensions[ServersExtension])
+}
@@ -0,0 +1,555 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/holydocs/servicefile/internal/openapi"
+	"github.com/holydocs/servicefile/internal/protobuf"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+// Annotations collects service and relationship annotations found in source files
+// and builds service files from them.
+type Annotations struct {
+	Services      []Service
+	Relationships []Relationship
+	// ProtoServices holds the gRPC services found in .proto files.
+	ProtoServices []protobuf.Service
+	// ProtoErrors holds the .proto files that couldn't be parsed, reported when a replies
+	// relationship implements one of their services.
+	ProtoErrors []*ProtoError
+	// Implementations maps annotated types to the gRPC services they implement.
+	Implementations map[Location]string
+	// Issues holds the problems that don't prevent building service files, e.g. unknown fields.
+	Issues []*AnnotationError
+	// FS holds the files referenced by annotations, e.g. OpenAPI documents. Paths are
+	// read from the disk when it is nil.
+	FS fs.FS
+}
+
+// NewAnnotations creates empty Annotations.
+func NewAnnotations() *Annotations {
+	return &Annotations{
+		Services:        make([]Service, 0),
+		Relationships:   make([]Relationship, 0),
+		Implementations: make(map[Location]string),
+	}
+}
+
+// Service represents a service:name annotation.
+type Service struct {
+	Name        string
+	Description string
+	System      string
+	Owner       string
+	Repository  string
+	Tags        []string
+	Extensions  servicefile.Extensions
+}
+
+func (s Service) String() string {
+	return fmt.Sprintf("name: %s, description: %s", s.Name, s.Description)
+}
+
+// Relationship represents a service:{action} or service:{service_name}:{action} annotation.
+type Relationship struct {
+	ServiceName string
+	Action      string
+	Participant string
+	Technology  string
+	Description string
+	Proto       string
+	Tags        []string
+	External    bool
+	Person      bool
+	Channel     string
+	Messages    []string
+	Schema      string
+	Operations  []string
+	Spec        string
+	Extensions  servicefile.Extensions
+	Location    Location
+}
+
+// Location points to the place in source code where an annotation was found.
+type Location struct {
+	File string
+	// TypeName is the name of the declaration the annotation documents, if any.
+	TypeName string
+}
+
+func (r Relationship) String() string {
+	return fmt.Sprintf("service_name: %s, action: %s, target_name: %s",
+		r.ServiceName,
+		r.Action,
+		r.Participant,
+	)
+}
+
+// error reports an invalid relationship along with the file it was found in.
+func (r Relationship) error(err error) error {
+	return &AnnotationError{Position: r.Location.File, Annotation: r.String(), Err: err}
+}
+
+// buildChannel returns the channel of sends and receives relationships.
+func (r Relationship) buildChannel() (*servicefile.Channel, error) {
+	if r.Channel == "" && len(r.Messages) == 0 && r.Schema == "" {
+		return nil, nil
+	}
+
+	if !servicefile.RelationshipAction(r.Action).IsAsync() {
+		return nil, fmt.Errorf("channel is only supported for %s and %s relationships",
+			servicefile.RelationshipActionSends, servicefile.RelationshipActionReceives)
+	}
+
+	if r.Channel == "" {
+		return nil, fmt.Errorf("channel name is required for messages and schema")
+	}
+
+	return &servicefile.Channel{
+		Name:     r.Channel,
+		Messages: r.Messages,
+		Schema:   r.Schema,
+	}, nil
+}
+
+// applySpec enriches a replies relationship with the OpenAPI document referenced by spec.
+func (r Relationship) applySpec(rel *servicefile.Relationship, fsys fs.FS) error {
+	if r.Action != servicefile.RelationshipActionReplies {
+		return fmt.Errorf("spec is only supported for %s relationships", servicefile.RelationshipActionReplies)
+	}
+
+	data, specPath, err := r.readSpec(fsys)
+	if err != nil {
+		return err
+	}
+
+	doc, err := openapi.Parse(specPath, data)
+	if err != nil {
+		return err
+	}
+
+	rel.Spec = specPath
+	if !filepath.IsAbs(specPath) {
+		rel.Spec = filepath.ToSlash(specPath)
+	}
+
+	openapi.Enrich(rel, doc)
+
+	return nil
+}
+
+// readSpec reads the OpenAPI document referenced by spec, relative to the annotated file
+// unless absolute, and returns its path relative to the parsed directory. Absolute paths
+// are always read from the disk.
+func (r Relationship) readSpec(fsys fs.FS) ([]byte, string, error) {
+	var (
+		specPath string
+		data     []byte
+		err      error
+	)
+
+	switch {
+	case filepath.IsAbs(r.Spec):
+		specPath = r.Spec
+		data, err = os.ReadFile(specPath)
+	case fsys == nil:
+		specPath = filepath.Join(filepath.Dir(r.Location.File), r.Spec)
+		data, err = os.ReadFile(specPath)
+	default:
+		specPath = path.Join(path.Dir(r.Location.File), filepath.ToSlash(r.Spec))
+		data, err = fs.ReadFile(fsys, specPath)
+	}
+
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		return nil, "", fmt.Errorf("spec file %s not found", specPath)
+	}
+
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read spec file %s: %w", specPath, err)
+	}
+
+	return data, specPath, nil
+}
+
+// applyGRPCService fills a replies relationship with the RPCs of the gRPC service
+// its type implements. Operations written in the annotation are kept.
+func (a *Annotations) applyGRPCService(rel *servicefile.Relationship, name string) error {
+	if rel.Technology == "" {
+		rel.Technology = "grpc"
+	}
+
+	if rel.Proto == "" {
+		rel.Proto = "grpc"
+	}
+
+	for _, protoErr := range a.ProtoErrors {
+		for _, service := range protoErr.Services {
+			if service == name {
+				return protoErr.SourceError
+			}
+		}
+	}
+
+	var matches []protobuf.Service
+	for _, s := range a.ProtoServices {
+		if s.Name == name {
+			matches = append(matches, s)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil
+	case 1:
+	default:
+		names := make([]string, 0, len(matches))
+		for _, s := range matches {
+			names = append(names, s.FullName())
+		}
+
+		return fmt.Errorf("gRPC service %s is ambiguous: %s", name, strings.Join(names, ", "))
+	}
+
+	known := make(map[string]bool, len(rel.Operations))
+	for _, op := range rel.Operations {
+		known[op] = true
+	}
+
+	for _, op := range matches[0].Operations() {
+		if !known[op] {
+			rel.Operations = append(rel.Operations, op)
+		}
+	}
+
+	return nil
+}
+
+// ParseComment parses the text of a comment, including its comment markers,
+// and collects the annotation it contains.
+func (a *Annotations) ParseComment(comment string, loc Location) {
+	if !strings.Contains(comment, "service:") {
+		return
+	}
+
+	lines := strings.Split(comment, "\n")
+
+	switch {
+	case strings.Contains(comment, "service:name"):
+		a.parseServiceDefinition(lines, loc)
+	default:
+		a.parseRelationshipDefinition(lines, loc)
+	}
+}
+
+func (a *Annotations) parseServiceDefinition(lines []string, loc Location) {
+	var (
+		s       Service
+		unknown []string
+	)
+
+	for _, f := range parseFields(lines) {
+		switch f.key {
+		case "service":
+			if name, ok := strings.CutPrefix(f.value, "name "); ok {
+				s.Name = unquote(strings.TrimSpace(name))
+			}
+		case "description":
+			s.Description = f.text()
+		case "system":
+			s.System = f.text()
+		case "owner":
+			s.Owner = f.text()
+		case "repository":
+			s.Repository = f.text()
+		case "tags":
+			if tags := f.list(); len(tags) > 0 {
+				s.Tags = tags
+			}
+		default:
+			if servicefile.IsExtension(f.key) {
+				s.Extensions = SetExtension(s.Extensions, f.key, f.extensionValue())
+			} else {
+				unknown = append(unknown, f.key)
+			}
+		}
+	}
+
+	for _, key := range unknown {
+		a.addIssue(loc, s.String(), fmt.Errorf("unknown field %q", key))
+	}
+
+	if s.Name != "" {
+		a.Services = append(a.Services, s)
+	}
+}
+
+func (a *Annotations) parseRelationshipDefinition(lines []string, loc Location) {
+	r := Relationship{Location: loc}
+
+	var unknown []string
+
+	for _, f := range parseFields(lines) {
+		switch f.key {
+		case "service":
+			r.ServiceName, r.Action, r.Participant = extractRelationshipInfo("service:" + f.value)
+			r.Participant = unquote(r.Participant)
+		case "technology":
+			r.Technology = f.text()
+		case "description":
+			r.Description = f.text()
+		case "proto":
+			r.Proto = f.text()
+		case "tags":
+			if tags := f.list(); len(tags) > 0 {
+				r.Tags = tags
+			}
+		case "external":
+			r.External = f.bool()
+		case "person":
+			r.Person = f.bool()
+		case "channel":
+			r.Channel = f.text()
+		case "messages":
+			r.Messages = f.list()
+		case "schema":
+			r.Schema = f.text()
+		case "operation":
+			if operation := servicefile.NormalizeOperation(f.text()); operation != "" {
+				r.Operations = append(r.Operations, operation)
+			}
+		case "spec":
+			r.Spec = f.text()
+		default:
+			if servicefile.IsExtension(f.key) {
+				r.Extensions = SetExtension(r.Extensions, f.key, f.extensionValue())
+			} else {
+				unknown = append(unknown, f.key)
+			}
+		}
+	}
+
+	for _, key := range unknown {
+		a.addIssue(loc, r.String(), fmt.Errorf("unknown field %q", key))
+	}
+
+	if r.Action != "" && !knownActions[r.Action] {
+		a.addIssue(loc, r.String(), fmt.Errorf("unknown action %q", r.Action))
+	}
+
+	if r.Action != "" {
+		a.Relationships = append(a.Relationships, r)
+	}
+}
+
+// knownActions are the actions of relationships defined by the specification.
+var knownActions = map[string]bool{
+	servicefile.RelationshipActionUses:     true,
+	servicefile.RelationshipActionRequests: true,
+	servicefile.RelationshipActionReplies:  true,
+	servicefile.RelationshipActionSends:    true,
+	servicefile.RelationshipActionReceives: true,
+}
+
+func (a *Annotations) addIssue(loc Location, annotation string, err error) {
+	a.Issues = append(a.Issues, &AnnotationError{Position: loc.File, Annotation: annotation, Err: err})
+}
+
+// SetExtension sets an extension field, creating the extensions map when needed.
+func SetExtension(extensions servicefile.Extensions, key string, value any) servicefile.Extensions {
+	if extensions == nil {
+		extensions = make(servicefile.Extensions)
+	}
+
+	extensions[key] = value
+
+	return extensions
+}
+
+// ExtensionValue decodes the value of an extension field the same way annotations do.
+func ExtensionValue(value string) any {
+	return field{value: value}.extensionValue()
+}
+
+// extractRelationshipInfo extracts the service name, action, and target name from a comment.
+// Format: service:{service_name}:{action} [target_service] or service:{action} [target_service]
+// Example: service:database:uses PostgreSQL
+// Example: service:uses PostgreSQL
+func extractRelationshipInfo(comment string) (serviceName, action, targetName string) {
+	parts := strings.SplitN(comment, " ", 2)
+	serviceActionPart := parts[0]
+
+	serviceActionParts := strings.Split(serviceActionPart, ":")
+	if len(serviceActionParts) >= 3 {
+		// Format: service:{service_name}:{action}
+		serviceName = serviceActionParts[1]
+		action = serviceActionParts[2]
+	} else if len(serviceActionParts) == 2 {
+		// Format: service:{action}
+		action = serviceActionParts[1]
+	}
+
+	// Extract target name if present
+	if len(parts) > 1 {
+		targetName = strings.TrimSpace(parts[1])
+	}
+
+	return serviceName, action, targetName
+}
+
+// Build builds the service files described by the collected annotations.
+func (a *Annotations) Build() ([]*servicefile.ServiceFile, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	serviceFiles := make(map[string]*servicefile.ServiceFile)
+
+	for _, s := range a.Services {
+		serviceFiles[s.Name] = &servicefile.ServiceFile{
+			Version: servicefile.Version,
+			Info: servicefile.Info{
+				Name:        s.Name,
+				Description: s.Description,
+				System:      s.System,
+				Owner:       s.Owner,
+				Repository:  s.Repository,
+				Tags:        s.Tags,
+				Extensions:  s.Extensions,
+			},
+			Relationships: []servicefile.Relationship{},
+		}
+	}
+
+	for _, r := range a.Relationships {
+		serviceName, err := determineServiceName(r, serviceFiles)
+		if err != nil {
+			return nil, r.error(fmt.Errorf("failed to determine service name: %w", err))
+		}
+
+		if _, exists := serviceFiles[serviceName]; !exists {
+			serviceFiles[serviceName] = &servicefile.ServiceFile{
+				Version: servicefile.Version,
+				Info: servicefile.Info{
+					Name: serviceName,
+				},
+				Relationships: []servicefile.Relationship{},
+			}
+		}
+
+		relationship := servicefile.Relationship{
+			Action:      servicefile.RelationshipAction(r.Action),
+			Participant: r.Participant,
+		}
+
+		if r.Technology != "" {
+			relationship.Technology = r.Technology
+		}
+
+		if r.Description != "" {
+			relationship.Description = r.Description
+		}
+
+		if r.Proto != "" {
+			relationship.Proto = r.Proto
+		}
+
+		if len(r.Tags) > 0 {
+			relationship.Tags = r.Tags
+		}
+
+		if r.External {
+			relationship.External = r.External
+		}
+
+		if r.Person {
+			relationship.Person = r.Person
+		}
+
+		channel, err := r.buildChannel()
+		if err != nil {
+			return nil, r.error(err)
+		}
+
+		relationship.Channel = channel
+
+		if len(r.Operations) > 0 {
+			if !servicefile.RelationshipAction(r.Action).IsSync() {
+				return nil, r.error(fmt.Errorf("operations are only supported for %s and %s relationships",
+					servicefile.RelationshipActionRequests, servicefile.RelationshipActionReplies))
+			}
+
+			relationship.Operations = r.Operations
+		}
+
+		if len(r.Extensions) > 0 {
+			relationship.Extensions = r.Extensions
+		}
+
+		if r.Spec != "" {
+			if err := r.applySpec(&relationship, a.FS); err != nil {
+				return nil, r.error(err)
+			}
+		}
+
+		if name, ok := a.Implementations[r.Location]; ok && r.Action == servicefile.RelationshipActionReplies {
+			if err := a.applyGRPCService(&relationship, name); err != nil {
+				return nil, r.error(err)
+			}
+		}
+
+		serviceFiles[serviceName].Relationships = append(serviceFiles[serviceName].Relationships, relationship)
+	}
+
+	if len(serviceFiles) == 0 {
+		return nil, ErrNoServices
+	}
+
+	result := make([]*servicefile.ServiceFile, 0, len(serviceFiles))
+	for _, sf := range serviceFiles {
+		sf.Sort()
+		result = append(result, sf)
+	}
+
+	return result, nil
+}
+
+// Validate checks that relationships don't mix the explicit and implicit definition patterns.
+func (a *Annotations) Validate() error {
+	var (
+		hasExplicit bool
+		hasImplicit bool
+	)
+
+	for _, r := range a.Relationships {
+		if r.ServiceName != "" {
+			hasExplicit = true
+		} else {
+			hasImplicit = true
+		}
+	}
+
+	if hasExplicit && hasImplicit {
+		return ErrMixedPatterns
+	}
+
+	return nil
+}
+
+func determineServiceName(r Relationship, serviceFiles map[string]*servicefile.ServiceFile) (string, error) {
+	if r.ServiceName != "" {
+		return r.ServiceName, nil
+	}
+
+	for name := range serviceFiles {
+		return name, nil
+	}
+
+	return "", fmt.Errorf("no service name found for relationship: %s", r)
+}
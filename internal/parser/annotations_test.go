@@ -0,0 +1,777 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/holydocs/servicefile/internal/protobuf"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+func TestParseComment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                  string
+		commentGroup          string
+		expectedServices      []Service
+		expectedRelationships []Relationship
+	}{
+		{
+			name: "parse service info with all fields",
+			commentGroup: `/*
+service:name UserService
+description: Handles user authentication and profiles
+system: e-commerce-platform
+owner: team-auth
+repository: https://github.com/holydocs/servicefile
+tags: auth, user-management, microservice
+*/`,
+			expectedServices: []Service{
+				{
+					Name:        "UserService",
+					Description: "Handles user authentication and profiles",
+					System:      "e-commerce-platform",
+					Owner:       "team-auth",
+					Repository:  "https://github.com/holydocs/servicefile",
+					Tags:        []string{"auth", "user-management", "microservice"},
+				},
+			},
+			expectedRelationships: []Relationship{},
+		},
+		{
+			name: "parse relationship with all fields",
+			commentGroup: `/*
+service:uses PostgreSQL
+description: Stores user data and authentication tokens
+technology:postgresql
+proto:tcp
+*/`,
+			expectedServices: []Service{},
+			expectedRelationships: []Relationship{
+				{
+					ServiceName: "",
+					Action:      "uses",
+					Participant: "PostgreSQL",
+					Description: "Stores user data and authentication tokens",
+					Technology:  "postgresql",
+					Proto:       "tcp",
+				},
+			},
+		},
+		{
+			name: "parse relationship with person attribute",
+			commentGroup: `/*
+service:replies User
+description: Replies to user requests via web interface
+technology:http
+person:true
+*/`,
+			expectedServices: []Service{},
+			expectedRelationships: []Relationship{
+				{
+					ServiceName: "",
+					Action:      "replies",
+					Participant: "User",
+					Description: "Replies to user requests via web interface",
+					Technology:  "http",
+					Person:      true,
+				},
+			},
+		},
+		{
+			name: "parse relationship with person attribute false",
+			commentGroup: `/*
+service:replies OtherService
+description: Replies to other service requests
+technology:grpc
+person:false
+*/`,
+			expectedServices: []Service{},
+			expectedRelationships: []Relationship{
+				{
+					ServiceName: "",
+					Action:      "replies",
+					Participant: "OtherService",
+					Description: "Replies to other service requests",
+					Technology:  "grpc",
+					Person:      false,
+				},
+			},
+		},
+		{
+			name: "parse service info with multi-line description and quoted tags",
+			commentGroup: `/*
+service:name UserService
+description: Handles user authentication
+  and profiles.
+
+  Owned by the identity team.
+tags: "auth, identity", user-management, escaped\, tag
+*/`,
+			expectedServices: []Service{
+				{
+					Name:        "UserService",
+					Description: "Handles user authentication and profiles.\n\nOwned by the identity team.",
+					Tags:        []string{"auth, identity", "user-management", "escaped, tag"},
+				},
+			},
+			expectedRelationships: []Relationship{},
+		},
+		{
+			name: "parse relationship with literal block description and quoted values",
+			commentGroup: `// service:uses "Payment Gateway"
+// description: |
+//   Charges cards.
+//   Refunds orders.
+// technology: "stripe, v2"
+// proto: 'https'`,
+			expectedServices: []Service{},
+			expectedRelationships: []Relationship{
+				{
+					ServiceName: "",
+					Action:      "uses",
+					Participant: "Payment Gateway",
+					Description: "Charges cards.\nRefunds orders.",
+					Technology:  "stripe, v2",
+					Proto:       "https",
+				},
+			},
+		},
+		{
+			name: "parse extension fields",
+			commentGroup: `/*
+service:name Orders
+description: Handles orders
+x-slo-tier: gold
+x-pagerduty: PD12345
+x-tier: 1
+x-regions: [eu, us]
+x-runbook: |
+  https://runbooks.example.com/orders
+  Restart pods first.
+*/`,
+			expectedServices: []Service{
+				{
+					Name:        "Orders",
+					Description: "Handles orders",
+					Extensions: servicefile.Extensions{
+						"x-slo-tier":  "gold",
+						"x-pagerduty": "PD12345",
+						"x-tier":      1,
+						"x-regions":   []any{"eu", "us"},
+						"x-runbook":   "https://runbooks.example.com/orders\nRestart pods first.",
+					},
+				},
+			},
+			expectedRelationships: []Relationship{},
+		},
+		{
+			name: "parse relationship extension fields",
+			commentGroup: `// service:uses PostgreSQL
+// technology:postgresql
+// x-data-classification: "confidential"
+// x-critical: true
+// unknown: ignored`,
+			expectedServices: []Service{},
+			expectedRelationships: []Relationship{
+				{
+					Action:      "uses",
+					Participant: "PostgreSQL",
+					Technology:  "postgresql",
+					Extensions: servicefile.Extensions{
+						"x-data-classification": "confidential",
+						"x-critical":            true,
+					},
+				},
+			},
+		},
+		{
+			name: "parse relationship with channel",
+			commentGroup: `/*
+service:sends Kafka
+description: Publishes order events
+technology:kafka
+channel: orders.events
+messages: OrderCreated, OrderCancelled
+schema: schemas/order-events.avsc
+*/`,
+			expectedServices: []Service{},
+			expectedRelationships: []Relationship{
+				{
+					Action:      "sends",
+					Participant: "Kafka",
+					Description: "Publishes order events",
+					Technology:  "kafka",
+					Channel:     "orders.events",
+					Messages:    []string{"OrderCreated", "OrderCancelled"},
+					Schema:      "schemas/order-events.avsc",
+				},
+			},
+		},
+		{
+			name: "parse relationship with operations",
+			commentGroup: `/*
+service:replies
+description: Provides user management APIs
+technology:http-server
+proto:http
+operation: GET /users/{id}
+operation: post   /users
+operation: query GetUser
+*/`,
+			expectedServices: []Service{},
+			expectedRelationships: []Relationship{
+				{
+					Action:      "replies",
+					Description: "Provides user management APIs",
+					Technology:  "http-server",
+					Proto:       "http",
+					Operations:  []string{"GET /users/{id}", "POST /users", "query GetUser"},
+				},
+			},
+		},
+		{
+			name:                  "parse empty comment group",
+			commentGroup:          `/* */`,
+			expectedServices:      []Service{},
+			expectedRelationships: []Relationship{},
+		},
+		{
+			name: "parse comments starting with //",
+			commentGroup: `// service:name Example
+// description: Example service for exampling stuff.`,
+			expectedServices: []Service{
+				{
+					Name:        "Example",
+					Description: "Example service for exampling stuff.",
+				},
+			},
+			expectedRelationships: []Relationship{},
+		},
+		{
+			name: "parse comments starting with // including owner",
+			commentGroup: `// service:name Example
+// description: Example service for exampling stuff.
+// owner: team-example`,
+			expectedServices: []Service{
+				{
+					Name:        "Example",
+					Description: "Example service for exampling stuff.",
+					Owner:       "team-example",
+				},
+			},
+			expectedRelationships: []Relationship{},
+		},
+		{
+			name: "parse mixed comments: regular golang comments first, then service comments with /* */",
+			commentGroup: `// User represents a user in the system
+// This struct contains all user-related fields
+/*
+service:uses PostgreSQL
+description: Stores user data and authentication tokens
+technology:postgresql
+proto:tcp
+*/`,
+			expectedServices: []Service{},
+			expectedRelationships: []Relationship{
+				{
+					ServiceName: "",
+					Action:      "uses",
+					Participant: "PostgreSQL",
+					Description: "Stores user data and authentication tokens",
+					Technology:  "postgresql",
+					Proto:       "tcp",
+				},
+			},
+		},
+		{
+			name: "parse mixed comments: regular golang comments first, then service comments with //",
+			commentGroup: `// User represents a user in the system
+// This struct contains all user-related fields
+// service:uses PostgreSQL
+// description: Stores user data and authentication tokens
+// technology:postgresql
+// proto:tcp`,
+			expectedServices: []Service{},
+			expectedRelationships: []Relationship{
+				{
+					ServiceName: "",
+					Action:      "uses",
+					Participant: "PostgreSQL",
+					Description: "Stores user data and authentication tokens",
+					Technology:  "postgresql",
+					Proto:       "tcp",
+				},
+			},
+		},
+		{
+			name: "parse mixed comments: service comments with /* */ first, then regular golang comments",
+			commentGroup: `/*
+service:name Example
+description: Example service for exampling stuff.
+*/
+// User represents a user in the system
+// This struct contains all user-related fields`,
+			expectedServices: []Service{
+				{
+					Name:        "Example",
+					Description: "Example service for exampling stuff.",
+				},
+			},
+			expectedRelationships: []Relationship{},
+		},
+		{
+			name: "parse mixed comments: service comments with // first, then regular golang comments",
+			commentGroup: `// service:name Example
+// description: Example service for exampling stuff.
+// User represents a user in the system
+// This struct contains all user-related fields`,
+			expectedServices: []Service{
+				{
+					Name:        "Example",
+					Description: "Example service for exampling stuff.",
+				},
+			},
+			expectedRelationships: []Relationship{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAnnotations()
+			a.ParseComment(tt.commentGroup, Location{})
+
+			if !compareServices(a.Services, tt.expectedServices) {
+				t.Errorf("ParseComment() services = %+v, want %+v", a.Services, tt.expectedServices)
+			}
+
+			if !compareRelationships(a.Relationships, tt.expectedRelationships) {
+				t.Errorf("ParseComment() relationships = %+v, want %+v", a.Relationships, tt.expectedRelationships)
+			}
+		})
+	}
+}
+
+func TestBuildChannel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		commentGroup    string
+		expectedChannel *servicefile.Channel
+		expectError     bool
+	}{
+		{
+			name: "receives with channel",
+			commentGroup: `// service:receives RabbitMQ
+// technology:rabbitmq
+// channel: orders-exchange/order.created
+// messages: OrderCreated`,
+			expectedChannel: &servicefile.Channel{
+				Name:     "orders-exchange/order.created",
+				Messages: []string{"OrderCreated"},
+			},
+		},
+		{
+			name: "sends without channel",
+			commentGroup: `// service:sends Kafka
+// technology:kafka`,
+		},
+		{
+			name: "channel on uses relationship",
+			commentGroup: `// service:uses PostgreSQL
+// channel: orders`,
+			expectError: true,
+		},
+		{
+			name: "messages without channel name",
+			commentGroup: `// service:sends Kafka
+// messages: OrderCreated`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAnnotations()
+			a.ParseComment("// service:name Orders", Location{})
+			a.ParseComment(tt.commentGroup, Location{})
+
+			result, err := a.Build()
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if channel := result[0].Relationships[0].Channel; !reflect.DeepEqual(channel, tt.expectedChannel) {
+				t.Errorf("Build() channel = %+v, want %+v", channel, tt.expectedChannel)
+			}
+		})
+	}
+}
+
+func TestBuildOperations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		commentGroup       string
+		expectedOperations []string
+		expectError        bool
+	}{
+		{
+			name: "requests with operations",
+			commentGroup: `// service:requests UserService
+// technology:grpc
+// operation: user.v1.UserService/GetUser
+// operation: user.v1.UserService/ListUsers`,
+			expectedOperations: []string{"user.v1.UserService/GetUser", "user.v1.UserService/ListUsers"},
+		},
+		{
+			name: "operations on sends relationship",
+			commentGroup: `// service:sends Kafka
+// operation: GET /users`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAnnotations()
+			a.ParseComment("// service:name Orders", Location{})
+			a.ParseComment(tt.commentGroup, Location{})
+
+			result, err := a.Build()
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if operations := result[0].Relationships[0].Operations; !reflect.DeepEqual(operations, tt.expectedOperations) {
+				t.Errorf("Build() operations = %v, want %v", operations, tt.expectedOperations)
+			}
+		})
+	}
+}
+
+func TestBuildSpec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		commentGroup string
+	}{
+		{
+			name: "missing spec file",
+			commentGroup: `// service:replies
+// spec: missing.yaml`,
+		},
+		{
+			name: "spec on requests relationship",
+			commentGroup: `// service:requests UserService
+// spec: spec/api/openapi.yaml`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAnnotations()
+			a.ParseComment("// service:name Orders", Location{})
+			a.ParseComment(tt.commentGroup, Location{File: "testdata/main.go"})
+
+			if _, err := a.Build(); err == nil {
+				t.Errorf("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestBuildSpecPath(t *testing.T) {
+	t.Parallel()
+
+	a := NewAnnotations()
+	a.FS = fstest.MapFS{
+		"services/users/openapi.yaml": {Data: []byte("openapi: 3.0.0\ninfo:\n  title: Users\npaths: {}\n")},
+	}
+
+	a.ParseComment("// service:name Users", Location{})
+	a.ParseComment("// service:replies\n// spec: ../openapi.yaml", Location{File: "services/users/api/server.go"})
+
+	serviceFiles, err := a.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if spec := serviceFiles[0].Relationships[0].Spec; spec != "services/users/openapi.yaml" {
+		t.Errorf("Build() spec = %q, want the path relative to the parsed directory", spec)
+	}
+}
+
+func TestBuildGRPC(t *testing.T) {
+	t.Parallel()
+
+	userService := protobuf.Service{
+		Name:    "UserService",
+		Package: "user.v1",
+		Methods: []protobuf.Method{{Name: "GetUser"}, {Name: "ListUsers"}},
+	}
+
+	tests := []struct {
+		name               string
+		commentGroup       string
+		protoServices      []protobuf.Service
+		protoErrors        []*ProtoError
+		expectedTechnology string
+		expectedOperations []string
+		expectError        bool
+	}{
+		{
+			name: "operations from proto",
+			commentGroup: `// service:replies
+// technology: grpc-server
+// operation: user.v1.UserService/GetUser`,
+			protoServices:      []protobuf.Service{userService},
+			expectedTechnology: "grpc-server",
+			expectedOperations: []string{"user.v1.UserService/GetUser", "user.v1.UserService/ListUsers"},
+		},
+		{
+			name:               "proto not found",
+			commentGroup:       `// service:replies`,
+			expectedTechnology: "grpc",
+		},
+		{
+			name:         "ambiguous service",
+			commentGroup: `// service:replies`,
+			protoServices: []protobuf.Service{
+				userService,
+				{Name: "UserService", Package: "user.v2"},
+			},
+			expectError: true,
+		},
+		{
+			name:          "service of unparsable proto",
+			commentGroup:  `// service:replies`,
+			protoServices: []protobuf.Service{userService},
+			protoErrors: []*ProtoError{{
+				SourceError: &SourceError{Path: "user.proto", Err: errors.New("unexpected token")},
+				Services:    []string{"UserService"},
+			}},
+			expectError: true,
+		},
+		{
+			name:          "other unparsable proto",
+			commentGroup:  `// service:replies`,
+			protoServices: []protobuf.Service{userService},
+			protoErrors: []*ProtoError{{
+				SourceError: &SourceError{Path: "vendor.proto", Err: errors.New("unexpected token")},
+				Services:    []string{"Health"},
+			}},
+			expectedTechnology: "grpc",
+			expectedOperations: []string{"user.v1.UserService/GetUser", "user.v1.UserService/ListUsers"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := Location{File: "server.go", TypeName: "Server"}
+
+			a := NewAnnotations()
+			a.ProtoServices = tt.protoServices
+			a.ProtoErrors = tt.protoErrors
+			a.Implementations[loc] = "UserService"
+			a.ParseComment("// service:name Users", Location{})
+			a.ParseComment(tt.commentGroup, loc)
+
+			result, err := a.Build()
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			rel := result[0].Relationships[0]
+
+			if rel.Technology != tt.expectedTechnology || rel.Proto != "grpc" {
+				t.Errorf("Build() technology = %s, proto = %s, want %s, grpc", rel.Technology, rel.Proto, tt.expectedTechnology)
+			}
+
+			if !reflect.DeepEqual(rel.Operations, tt.expectedOperations) {
+				t.Errorf("Build() operations = %v, want %v", rel.Operations, tt.expectedOperations)
+			}
+		})
+	}
+}
+
+// compareStringSlices compares two string slices for equality
+func compareStringSlices(actual, expected []string) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+
+	// Create maps for O(1) lookup
+	actualMap := make(map[string]bool)
+	expectedMap := make(map[string]bool)
+
+	for _, s := range actual {
+		actualMap[s] = true
+	}
+
+	for _, s := range expected {
+		expectedMap[s] = true
+	}
+
+	// Compare maps
+	if len(actualMap) != len(expectedMap) {
+		return false
+	}
+
+	for s := range actualMap {
+		if !expectedMap[s] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareServices compares two service slices for equality
+func compareServices(actual, expected []Service) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+
+	// Compare services (order doesn't matter for this test)
+	for _, expectedService := range expected {
+		found := false
+		for _, actualService := range actual {
+			if actualService.Name == expectedService.Name &&
+				actualService.Description == expectedService.Description &&
+				actualService.System == expectedService.System &&
+				actualService.Owner == expectedService.Owner &&
+				actualService.Repository == expectedService.Repository &&
+				compareStringSlices(actualService.Tags, expectedService.Tags) &&
+				reflect.DeepEqual(actualService.Extensions, expectedService.Extensions) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareRelationships compares two relationship slices for equality
+func compareRelationships(actual, expected []Relationship) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+
+	// Compare relationships (order doesn't matter for this test)
+	for _, expectedRel := range expected {
+		found := false
+		for _, actualRel := range actual {
+			if actualRel.ServiceName == expectedRel.ServiceName &&
+				actualRel.Action == expectedRel.Action &&
+				actualRel.Participant == expectedRel.Participant &&
+				actualRel.Technology == expectedRel.Technology &&
+				actualRel.Description == expectedRel.Description &&
+				actualRel.Proto == expectedRel.Proto &&
+				actualRel.External == expectedRel.External &&
+				actualRel.Person == expectedRel.Person &&
+				actualRel.Channel == expectedRel.Channel &&
+				compareStringSlices(actualRel.Messages, expectedRel.Messages) &&
+				actualRel.Schema == expectedRel.Schema &&
+				compareStringSlices(actualRel.Operations, expectedRel.Operations) &&
+				actualRel.Spec == expectedRel.Spec &&
+				reflect.DeepEqual(actualRel.Extensions, expectedRel.Extensions) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestParseCommentIssues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		commentGroup   string
+		expectedIssues []string
+	}{
+		{
+			name: "known fields",
+			commentGroup: `// service:uses PostgreSQL
+// technology: postgresql
+// x-tier: 1`,
+		},
+		{
+			name: "url in a description",
+			commentGroup: `// service:requests Users
+// description: Loads users, see
+// https://users.example.com/docs`,
+		},
+		{
+			name: "unknown service field",
+			commentGroup: `// service:name Orders
+// descripton: Manages orders`,
+			expectedIssues: []string{`a.go: invalid annotation name: Orders, description: : unknown field "descripton"`},
+		},
+		{
+			name: "unknown action",
+			commentGroup: `// service:calls Payments
+// proto: http`,
+			expectedIssues: []string{`a.go: invalid annotation service_name: , action: calls, target_name: Payments: unknown action "calls"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAnnotations()
+			a.ParseComment(tt.commentGroup, Location{File: "a.go"})
+
+			var issues []string
+			for _, issue := range a.Issues {
+				issues = append(issues, issue.Error())
+			}
+
+			if !reflect.DeepEqual(issues, tt.expectedIssues) {
+				t.Errorf("ParseComment() issues = %q, want %q", issues, tt.expectedIssues)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package csharp
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/holydocs/servicefile/pkg/language"
+)
+
+func init() {
+	language.MustRegister(NewParser())
+}
+
+// Parser finds the comments of C# source code.
+type Parser struct{}
+
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Name returns the name of the language.
+func (p *Parser) Name() string {
+	return "csharp"
+}
+
+// Extensions returns the extensions of C# source files.
+func (p *Parser) Extensions() []string {
+	return []string{".cs"}
+}
+
+// Comments returns the comments of a C# source file.
+// XML documentation comments (///) are returned as // comments without their text tags.
+func (p *Parser) Comments(_ string, src []byte) ([]language.Comment, error) {
+	return syntax.Comments(string(src)), nil
+}
+
+var syntax = &language.Syntax{
+	LineComment: "//",
+	BlockStart:  "/*",
+	BlockEnd:    "*/",
+	LineText: func(text string) string {
+		if strings.HasPrefix(text, "/") && !strings.HasPrefix(text, "//") {
+			return documentationText(text[1:])
+		}
+
+		return text
+	},
+	Literal:     skipLiteral,
+	Declaration: declarationPattern,
+}
+
+// skipLiteral returns the index following the string or character literal starting at i.
+func skipLiteral(src string, i int) int {
+	switch {
+	case strings.HasPrefix(src[i:], `"""`):
+		return skipRawString(src, i)
+	case strings.HasPrefix(src[i:], `@"`), strings.HasPrefix(src[i:], `$@"`), strings.HasPrefix(src[i:], `@$"`):
+		return skipVerbatimString(src, i+strings.IndexByte(src[i:], '"'))
+	case src[i] == '"' || src[i] == '\'':
+		return language.SkipString(src, i)
+	}
+
+	return i
+}
+
+// documentationTags are the XML tags holding the text of documentation comments.
+var documentationTags = regexp.MustCompile(`</?(?:summary|remarks|para)\s*>`)
+
+// documentationText removes the text tags of a line of an XML documentation comment
+// and decodes its entities, e.g. &lt;.
+func documentationText(line string) string {
+	return html.UnescapeString(documentationTags.ReplaceAllString(line, ""))
+}
+
+// skipVerbatimString returns the index following the verbatim string literal whose quote is at i.
+// A quote is escaped by doubling it.
+func skipVerbatimString(src string, i int) int {
+	for i++; i < len(src); i++ {
+		if src[i] != '"' {
+			continue
+		}
+
+		if i+1 < len(src) && src[i+1] == '"' {
+			i++
+			continue
+		}
+
+		return i + 1
+	}
+
+	return i
+}
+
+// skipRawString returns the index following the raw string literal starting at i,
+// which is delimited by three or more quotes.
+func skipRawString(src string, i int) int {
+	n := 0
+	for i+n < len(src) && src[i+n] == '"' {
+		n++
+	}
+
+	end := strings.Index(src[i+n:], strings.Repeat(`"`, n))
+	if end < 0 {
+		return len(src)
+	}
+
+	return i + n + end + n
+}
+
+var declarationPattern = regexp.MustCompile(`^\s*(?:\[[^\n]*\]\s*)*` +
+	`(?:(?:public|protected|private|internal|static|sealed|abstract|partial|readonly|unsafe|file|new|ref)\s+)*` +
+	`(?:class|interface|struct|enum|record(?:\s+(?:class|struct))?)\s+(\w+)`)
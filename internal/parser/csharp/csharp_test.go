@@ -0,0 +1,83 @@
+package csharp
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/holydocs/servicefile/internal/parser"
+	"github.com/holydocs/servicefile/pkg/language"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	serviceFiles, err := parser.Parse(filepath.Join("testdata", "service"), true, false, parser.FromComments(NewParser()))
+	require.NoError(t, err)
+	require.Len(t, serviceFiles, 1)
+
+	sf := serviceFiles[0]
+
+	assert.Equal(t, servicefile.Info{
+		Name:        "Billing",
+		Description: "Issues invoices",
+		Owner:       "team-billing",
+	}, sf.Info)
+
+	assert.Equal(t, []servicefile.Relationship{
+		{
+			Action:      servicefile.RelationshipActionReplies,
+			Participant: "Customer",
+			Description: "Serves invoices & receipts",
+			Technology:  "http-server",
+			Proto:       "http",
+			Person:      true,
+		},
+		{
+			Action:      servicefile.RelationshipActionUses,
+			Participant: "SqlServer",
+			Description: "Stores invoices",
+			Technology:  "sqlserver",
+		},
+	}, sf.Relationships)
+}
+
+func TestComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		src      string
+		expected []language.Comment
+	}{
+		{
+			name: "xml documentation",
+			src:  "/// <summary>a: b &lt;c&gt;</summary>\n[Serializable]\npublic partial record struct Point;\n",
+			expected: []language.Comment{
+				{Text: "// a: b <c>", Declaration: "Point"},
+			},
+		},
+		{
+			name: "block comment",
+			src:  "/**\n * a: b\n */\nstatic class Helpers {}\n",
+			expected: []language.Comment{
+				{Text: "/*\na: b\n */", Declaration: "Helpers"},
+			},
+		},
+		{
+			name: "comment markers in literals",
+			src:  "var a = \"// x\\\"\"; var b = $@\"\"\"/* {y}\"; var c = \"\"\"\"\n\"\"\" //\n\"\"\"\"; var d = '\\'';\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, syntax.Comments(tt.src))
+		})
+	}
+}
@@ -0,0 +1,8 @@
+namespace Billing.Data;
+
+/// <summary>service:uses SqlServer</summary>
+/// <remarks>
+/// description: Stores invoices
+/// technology: sqlserver
+/// </remarks>
+internal record Store(string ConnectionString);
@@ -0,0 +1,22 @@
+// service:name Billing
+// description: Issues invoices
+// owner: team-billing
+
+namespace Billing;
+
+/// <summary>
+/// service:replies Customer
+/// description: Serves invoices &amp; receipts
+/// technology: http-server
+/// proto: http
+/// person: true
+/// </summary>
+[ApiController]
+public sealed class InvoicesController
+{
+    private const string Path = @"C:\invoices\""// service:uses NotAnnotation""";
+    private const string Raw = """
+        /* service:uses AlsoNotAnnotation */
+        """;
+    private const char Quote = '"';
+}
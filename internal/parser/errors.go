@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoServices is returned when no annotations are found.
+	ErrNoServices = errors.New("no services found")
+	// ErrMixedPatterns is returned when relationships mix the service:name:action and service:action patterns.
+	ErrMixedPatterns = errors.New("mixed relationship definition patterns detected: " +
+		"some relationships use explicit patterns (service:name:action) while others use implicit patterns (service:action)")
+)
+
+// SourceError reports a source file that can't be read or parsed.
+type SourceError struct {
+	Path string
+	Err  error
+}
+
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %v", e.Path, e.Err)
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// ProtoError reports a .proto file that can't be parsed. It only fails parsing when a replies
+// relationship implements one of the gRPC services of the file.
+type ProtoError struct {
+	*SourceError
+	// Services are the names of the services the file declares, as far as they can be found.
+	Services []string
+}
+
+// AnnotationError reports an annotation that is invalid or, in strict mode, questionable.
+type AnnotationError struct {
+	// Position is the file, or the file, line and column, the annotation was found at.
+	Position string
+	// Annotation describes the annotation, if known.
+	Annotation string
+	Err        error
+}
+
+func (e *AnnotationError) Error() string {
+	if e.Annotation == "" {
+		return fmt.Sprintf("%s: %v", e.Position, e.Err)
+	}
+
+	return fmt.Sprintf("%s: invalid annotation %s: %v", e.Position, e.Annotation, e.Err)
+}
+
+func (e *AnnotationError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,326 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// field represents a single "key: value" pair of an annotation.
+type field struct {
+	key   string
+	value string
+	// multiline is set when the value spans several lines, such values are never unquoted.
+	multiline bool
+}
+
+// text returns the value of the field with quotes removed.
+func (f field) text() string {
+	if f.multiline {
+		return f.value
+	}
+
+	return unquote(f.value)
+}
+
+// list returns the value of the field split by commas. Commas inside quotes
+// or escaped with a backslash don't split the value.
+func (f field) list() []string {
+	return splitList(f.value)
+}
+
+// bool returns the value of the field as a boolean.
+func (f field) bool() bool {
+	value := f.text()
+	return value == "true" || value == "yes" || value == "1"
+}
+
+// extensionValue returns the value of an extension field. Single line values
+// are decoded as YAML, so numbers, booleans and flow sequences keep their types.
+func (f field) extensionValue() any {
+	if f.multiline || f.value == "" {
+		return f.value
+	}
+
+	var value any
+	if err := yaml.Unmarshal([]byte(f.value), &value); err != nil || value == nil {
+		return f.text()
+	}
+
+	return value
+}
+
+var keyPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*):`)
+
+// commentLine is a line of comment text along with its indentation.
+type commentLine struct {
+	text   string
+	indent int
+	// comment identifies the /* */ block or the run of // lines the line belongs to.
+	comment int
+}
+
+// parseFields splits the comment lines into annotation fields.
+//
+// The annotation starts at the service key and its keys are the lines at the indentation
+// of the service key, or of the first key of each following comment.
+// A value continues on the following lines that are indented deeper than its key.
+// Continuation lines are joined with spaces and empty lines separate paragraphs.
+// A value of "|" starts a literal block, which keeps the line breaks of the
+// following indented lines.
+func parseFields(lines []string) []field {
+	comments := make([]commentLine, 0, len(lines))
+
+	var (
+		comment int
+		inBlock bool
+		lineRun bool
+	)
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case inBlock:
+		case strings.HasPrefix(trimmed, "/*"):
+			comment++
+			inBlock = true
+		case strings.HasPrefix(trimmed, "//"):
+			if !lineRun {
+				comment++
+			}
+		}
+
+		c := extractCommentLine(line)
+		c.comment = comment
+		comments = append(comments, c)
+
+		lineRun = !inBlock && strings.HasPrefix(trimmed, "//")
+		if inBlock && strings.HasSuffix(trimmed, "*/") {
+			inBlock = false
+		}
+	}
+
+	var (
+		fields []field
+		// keyIndent is the indentation of the keys in the comment keyComment, -1 until
+		// the service key the annotation starts with is found.
+		keyIndent  = -1
+		keyComment int
+	)
+
+	for i := 0; i < len(comments); i++ {
+		c := comments[i]
+
+		match := keyPattern.FindStringSubmatch(c.text)
+
+		// Lines preceding the annotation or indented differently than its keys are text,
+		// and so are URLs, e.g. https://example.com.
+		switch {
+		case match == nil || strings.HasPrefix(c.text[len(match[0]):], "//"):
+			continue
+		case keyIndent < 0 && match[1] != "service":
+			continue
+		case keyIndent < 0 || c.comment != keyComment:
+			keyIndent, keyComment = c.indent, c.comment
+		case c.indent != keyIndent:
+			continue
+		}
+
+		f := field{
+			key:   match[1],
+			value: strings.TrimSpace(c.text[len(match[0]):]),
+		}
+
+		end := continuationEnd(comments, i)
+		if end > i {
+			continuation := comments[i+1 : end+1]
+
+			if f.value == "|" {
+				f.value = literalBlock(continuation)
+			} else {
+				f.value = foldedBlock(f.value, continuation)
+			}
+
+			f.multiline = true
+			i = end
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+// continuationEnd returns the index of the last line continuing the value of the key line at i.
+func continuationEnd(comments []commentLine, i int) int {
+	end := i
+
+	for j := i + 1; j < len(comments); j++ {
+		c := comments[j]
+
+		if c.text == "" {
+			continue
+		}
+
+		if c.comment != comments[i].comment || c.indent <= comments[i].indent {
+			break
+		}
+
+		end = j
+	}
+
+	return end
+}
+
+func literalBlock(lines []commentLine) string {
+	indent := -1
+	for _, l := range lines {
+		if l.text != "" && (indent < 0 || l.indent < indent) {
+			indent = l.indent
+		}
+	}
+
+	result := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if l.text == "" {
+			result = append(result, "")
+			continue
+		}
+
+		result = append(result, strings.Repeat(" ", l.indent-indent)+l.text)
+	}
+
+	return strings.Join(result, "\n")
+}
+
+func foldedBlock(first string, lines []commentLine) string {
+	var (
+		paragraphs []string
+		words      []string
+	)
+
+	if first != "" {
+		words = append(words, first)
+	}
+
+	for _, l := range lines {
+		if l.text == "" {
+			if len(words) > 0 {
+				paragraphs = append(paragraphs, strings.Join(words, " "))
+				words = nil
+			}
+
+			continue
+		}
+
+		words = append(words, l.text)
+	}
+
+	if len(words) > 0 {
+		paragraphs = append(paragraphs, strings.Join(words, " "))
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// extractCommentLine removes comment markers from a line, keeping its indentation.
+func extractCommentLine(line string) commentLine {
+	text := strings.TrimRight(line, " \t\r")
+	trimmed := strings.TrimLeft(text, " \t")
+	indent := len(text) - len(trimmed)
+
+	if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") {
+		trimmed = trimmed[2:]
+		indent = 0
+	}
+
+	trimmed = strings.TrimSuffix(trimmed, "*/")
+	trimmed = strings.TrimRight(trimmed, " \t")
+
+	content := strings.TrimLeft(trimmed, " \t")
+	indent += len(trimmed) - len(content)
+
+	return commentLine{text: content, indent: indent}
+}
+
+// unquote removes quotes surrounding a value.
+// Double-quoted values support Go escape sequences, in single-quoted values a doubled single
+// quote stands for a quote.
+func unquote(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+
+	switch {
+	case value[0] == '"' && value[len(value)-1] == '"':
+		if s, err := strconv.Unquote(value); err == nil {
+			return s
+		}
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+
+	return value
+}
+
+// splitList splits a comma-separated list, trimming whitespace around items.
+// Items can be quoted the same way as values and a backslash escapes the following character.
+func splitList(value string) []string {
+	var (
+		items   []string
+		item    strings.Builder
+		pending string
+		quote   rune
+		escaped bool
+	)
+
+	add := func(r rune) {
+		item.WriteString(pending)
+		pending = ""
+		item.WriteRune(r)
+	}
+
+	runes := []rune(value)
+
+	for i, r := range runes {
+		switch {
+		case escaped:
+			add(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case quote == '\'' && r == quote && i+1 < len(runes) && runes[i+1] == quote:
+			// A doubled single quote stands for a quote, the second one is added as escaped.
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			add(r)
+		case (r == '"' || r == '\'') && item.Len() == 0:
+			quote = r
+		case r == ',':
+			if item.Len() > 0 {
+				items = append(items, item.String())
+			}
+			item.Reset()
+			pending = ""
+		case unicode.IsSpace(r):
+			// Whitespace is only kept when followed by more content of the item.
+			if item.Len() > 0 {
+				pending += string(r)
+			}
+		default:
+			add(r)
+		}
+	}
+
+	if item.Len() > 0 {
+		items = append(items, item.String())
+	}
+
+	return items
+}
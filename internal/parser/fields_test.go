@@ -0,0 +1,174 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		comment  string
+		expected []field
+	}{
+		{
+			name: "single line values",
+			comment: `// service:uses PostgreSQL
+// description: Stores data
+// technology:postgresql`,
+			expected: []field{
+				{key: "service", value: "uses PostgreSQL"},
+				{key: "description", value: "Stores data"},
+				{key: "technology", value: "postgresql"},
+			},
+		},
+		{
+			name: "continuation lines with // comments",
+			comment: `// service:uses PostgreSQL
+// description: Stores user data
+//   and authentication tokens.
+//
+//   Backed up daily.
+// technology:postgresql`,
+			expected: []field{
+				{key: "service", value: "uses PostgreSQL"},
+				{key: "description", value: "Stores user data and authentication tokens.\n\nBacked up daily.", multiline: true},
+				{key: "technology", value: "postgresql"},
+			},
+		},
+		{
+			name: "literal block with /* */ comments",
+			comment: `	/*
+	service:name Example
+	description: |
+	  First line.
+	    Indented line.
+
+	  Second paragraph.
+	owner: team
+	*/`,
+			expected: []field{
+				{key: "service", value: "name Example"},
+				{key: "description", value: "First line.\n  Indented line.\n\nSecond paragraph.", multiline: true},
+				{key: "owner", value: "team"},
+			},
+		},
+		{
+			name: "continuation does not cross comments",
+			comment: `/*
+service:name Example
+description: Example service.
+*/
+// User represents a user in the system`,
+			expected: []field{
+				{key: "service", value: "name Example"},
+				{key: "description", value: "Example service."},
+			},
+		},
+		{
+			name: "text preceding the annotation",
+			comment: `// Client calls the users API, see
+// https://users.example.com/docs
+//
+// service:requests Users
+// description: Loads users`,
+			expected: []field{
+				{key: "service", value: "requests Users"},
+				{key: "description", value: "Loads users"},
+			},
+		},
+		{
+			name: "url in a description",
+			comment: `	/*
+	  service:requests Users
+	  description: Loads users, documented at
+	https://users.example.com/docs
+	  technology: http
+	*/
+// description: Loads users, see
+// https://users.example.com/docs`,
+			expected: []field{
+				{key: "service", value: "requests Users"},
+				{key: "description", value: "Loads users, documented at"},
+				{key: "technology", value: "http"},
+				{key: "description", value: "Loads users, see"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parseFields(splitLines(tt.comment))
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("parseFields() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "auth, user-management,microservice", expected: []string{"auth", "user-management", "microservice"}},
+		{input: `"payments, EU", critical`, expected: []string{"payments, EU", "critical"}},
+		{input: `'a, b', 'it''s'`, expected: []string{"a, b", "it's"}},
+		{input: `a\, b, c`, expected: []string{"a, b", "c"}},
+		{input: `"say \"hi\"", it's`, expected: []string{`say "hi"`, "it's"}},
+		{input: "a,, b ,", expected: []string{"a", "b"}},
+		{input: `"  padded "`, expected: []string{"  padded "}},
+		{input: "", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := splitList(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("splitList(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: `"Handles orders, payments"`, expected: "Handles orders, payments"},
+		{input: `"Line\twith tab"`, expected: "Line\twith tab"},
+		{input: `'It''s quoted'`, expected: "It's quoted"},
+		{input: `not quoted`, expected: "not quoted"},
+		{input: `"unterminated`, expected: `"unterminated`},
+		{input: `"`, expected: `"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := unquote(tt.input); result != tt.expected {
+				t.Errorf("unquote(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func splitLines(s string) []string {
+	var lines []string
+
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			lines = append(lines, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(lines, s[start:])
+}
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+	"gopkg.in/yaml.v3"
+)
+
+// FormatService renders the annotation comment block declaring the service.
+func FormatService(info servicefile.Info) string {
+	var b strings.Builder
+
+	b.WriteString("/*\n")
+	b.WriteString("service:name " + info.Name + "\n")
+
+	writeField(&b, "description", info.Description)
+	writeField(&b, "system", info.System)
+	writeField(&b, "owner", info.Owner)
+	writeField(&b, "repository", info.Repository)
+	writeField(&b, "tags", formatList(info.Tags))
+	writeExtensions(&b, info.Extensions)
+
+	b.WriteString("*/\n")
+
+	return b.String()
+}
+
+// FormatRelationship renders the annotation comment block declaring the relationship.
+// The service name is only included when it's not empty, producing the explicit
+// service:{service_name}:{action} form.
+func FormatRelationship(serviceName string, r servicefile.Relationship) string {
+	var b strings.Builder
+
+	b.WriteString("/*\n")
+
+	b.WriteString("service:")
+	if serviceName != "" {
+		b.WriteString(serviceName + ":")
+	}
+	b.WriteString(string(r.Action))
+	if r.Participant != "" {
+		b.WriteString(" " + r.Participant)
+	}
+	b.WriteString("\n")
+
+	writeField(&b, "description", r.Description)
+	writeField(&b, "technology", r.Technology)
+	writeField(&b, "proto", r.Proto)
+	writeField(&b, "tags", formatList(r.Tags))
+
+	if r.External {
+		writeField(&b, "external", "true")
+	}
+
+	if r.Person {
+		writeField(&b, "person", "true")
+	}
+
+	if r.Channel != nil {
+		writeField(&b, "channel", r.Channel.Name)
+		writeField(&b, "messages", formatList(r.Channel.Messages))
+		writeField(&b, "schema", r.Channel.Schema)
+	}
+
+	for _, operation := range r.Operations {
+		writeField(&b, "operation", operation)
+	}
+
+	writeField(&b, "spec", r.Spec)
+
+	writeExtensions(&b, r.Extensions)
+
+	b.WriteString("*/\n")
+
+	return b.String()
+}
+
+func writeField(b *strings.Builder, key, value string) {
+	if value == "" {
+		return
+	}
+
+	if !strings.Contains(value, "\n") {
+		b.WriteString(key + ": " + value + "\n")
+		return
+	}
+
+	b.WriteString(key + ": |\n")
+
+	for _, line := range strings.Split(value, "\n") {
+		if line == "" {
+			b.WriteString("\n")
+			continue
+		}
+
+		b.WriteString("  " + line + "\n")
+	}
+}
+
+// formatList joins the items with commas, quoting the ones that would be split otherwise.
+func formatList(items []string) string {
+	formatted := make([]string, 0, len(items))
+
+	for _, item := range items {
+		if strings.ContainsAny(item, ",\\\"'") || strings.TrimSpace(item) != item {
+			item = strconv.Quote(item)
+		}
+
+		formatted = append(formatted, item)
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+func writeExtensions(b *strings.Builder, extensions servicefile.Extensions) {
+	keys := make([]string, 0, len(extensions))
+	for key := range extensions {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		writeField(b, key, formatExtensionValue(extensions[key]))
+	}
+}
+
+// formatExtensionValue renders the value as flow style YAML,
+// so it's decoded back with the same type.
+func formatExtensionValue(value any) string {
+	if s, ok := value.(string); ok && strings.Contains(s, "\n") {
+		return s
+	}
+
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+
+	node.Style |= yaml.FlowStyle
+
+	data, err := yaml.Marshal(&node)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return strings.TrimSpace(string(data))
+}
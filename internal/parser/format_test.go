@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+func TestFormatService(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		info     servicefile.Info
+		expected string
+	}{
+		{
+			name:     "name only",
+			info:     servicefile.Info{Name: "Example"},
+			expected: "/*\nservice:name Example\n*/\n",
+		},
+		{
+			name: "all fields",
+			info: servicefile.Info{
+				Name:        "UserService",
+				Description: "Handles users",
+				System:      "e-commerce",
+				Owner:       "team-auth",
+				Repository:  "https://github.com/holydocs/servicefile",
+				Tags:        []string{"auth", "users"},
+			},
+			expected: "/*\nservice:name UserService\ndescription: Handles users\nsystem: e-commerce\nowner: team-auth\n" +
+				"repository: https://github.com/holydocs/servicefile\ntags: auth, users\n*/\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := FormatService(tt.info); result != tt.expected {
+				t.Errorf("FormatService() = %q, want %q", result, tt.expected)
+			}
+
+			a := NewAnnotations()
+			a.ParseComment(FormatService(tt.info), Location{})
+
+			if len(a.Services) != 1 || a.Services[0].Name != tt.info.Name ||
+				a.Services[0].Repository != tt.info.Repository {
+				t.Errorf("FormatService() output does not parse back, got %+v", a.Services)
+			}
+		})
+	}
+}
+
+func TestFormatRelationship(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		serviceName string
+		rel         servicefile.Relationship
+		expected    string
+	}{
+		{
+			name: "implicit service",
+			rel: servicefile.Relationship{
+				Action:      servicefile.RelationshipActionUses,
+				Participant: "PostgreSQL",
+				Technology:  "postgresql",
+				Proto:       "tcp",
+			},
+			expected: "/*\nservice:uses PostgreSQL\ntechnology: postgresql\nproto: tcp\n*/\n",
+		},
+		{
+			name:        "explicit service",
+			serviceName: "auth",
+			rel: servicefile.Relationship{
+				Action:      servicefile.RelationshipActionReplies,
+				Participant: "User",
+				Description: "Provides web interface",
+				Technology:  "http",
+				Tags:        []string{"web", "public"},
+				Person:      true,
+			},
+			expected: "/*\nservice:auth:replies User\ndescription: Provides web interface\ntechnology: http\ntags: web, public\nperson: true\n*/\n",
+		},
+		{
+			name: "multi-line description and tags with commas",
+			rel: servicefile.Relationship{
+				Action:      servicefile.RelationshipActionUses,
+				Participant: "PostgreSQL",
+				Description: "Stores orders.\n\nBacked up daily.",
+				Tags:        []string{"critical", "eu, us"},
+			},
+			expected: "/*\nservice:uses PostgreSQL\ndescription: |\n  Stores orders.\n\n  Backed up daily.\ntags: critical, \"eu, us\"\n*/\n",
+		},
+		{
+			name: "openapi spec",
+			rel: servicefile.Relationship{
+				Action:     servicefile.RelationshipActionReplies,
+				Technology: "http-server",
+				Spec:       "api/openapi.yaml",
+			},
+			expected: "/*\nservice:replies\ntechnology: http-server\nspec: api/openapi.yaml\n*/\n",
+		},
+		{
+			name: "extension fields",
+			rel: servicefile.Relationship{
+				Action:      servicefile.RelationshipActionUses,
+				Participant: "PostgreSQL",
+				Extensions: servicefile.Extensions{
+					"x-tier":    1,
+					"x-regions": []any{"eu", "us"},
+					"x-code":    "007",
+				},
+			},
+			expected: "/*\nservice:uses PostgreSQL\nx-code: \"007\"\nx-regions: [eu, us]\nx-tier: 1\n*/\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := FormatRelationship(tt.serviceName, tt.rel); result != tt.expected {
+				t.Errorf("FormatRelationship() = %q, want %q", result, tt.expected)
+			}
+
+			a := NewAnnotations()
+			a.ParseComment(FormatRelationship(tt.serviceName, tt.rel), Location{})
+
+			if len(a.Relationships) != 1 || a.Relationships[0].Description != tt.rel.Description ||
+				!compareStringSlices(a.Relationships[0].Tags, tt.rel.Tags) ||
+				!reflect.DeepEqual(a.Relationships[0].Extensions, tt.rel.Extensions) {
+				t.Errorf("FormatRelationship() output does not parse back, got %+v", a.Relationships)
+			}
+		})
+	}
+}
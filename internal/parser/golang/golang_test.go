@@ -1,8 +1,13 @@
 package golang
 
 import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"reflect"
 	"testing"
 
+	"github.com/holydocs/servicefile/internal/parser"
 	"github.com/holydocs/servicefile/pkg/servicefile"
 )
 
@@ -138,6 +143,99 @@ func TestParse(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name:      "parse replies relationship with OpenAPI spec",
+			dir:       "testdata/spec",
+			recursive: true,
+			expectedResult: []*servicefile.ServiceFile{
+				{
+					Version: servicefile.Version,
+					Info: servicefile.Info{
+						Name:        "Users",
+						Description: "Manages users",
+					},
+					Relationships: []servicefile.Relationship{
+						{
+							Action:      servicefile.RelationshipActionReplies,
+							Description: "Provides user management APIs",
+							Technology:  "http-server",
+							Proto:       "http",
+							Operations:  []string{"GET /health", "GET /users", "POST /users", "GET /users/{id}"},
+							Spec:        "api/openapi.yaml",
+							Extensions:  servicefile.Extensions{"x-servers": []any{"https://users.example.com/v1"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "parse replies relationship implementing gRPC service",
+			dir:       "testdata/grpc",
+			recursive: true,
+			expectedResult: []*servicefile.ServiceFile{
+				{
+					Version: servicefile.Version,
+					Info: servicefile.Info{
+						Name:        "Users",
+						Description: "Manages users",
+					},
+					Relationships: []servicefile.Relationship{
+						{
+							Action:      servicefile.RelationshipActionReplies,
+							Description: "Provides user management APIs",
+							Technology:  "grpc",
+							Proto:       "grpc",
+							Operations:  []string{"user.v1.UserService/GetUser", "user.v1.UserService/ListUsers"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "parse typed annotations",
+			dir:       "testdata/typed",
+			recursive: true,
+			expectedResult: []*servicefile.ServiceFile{
+				{
+					Version: servicefile.Version,
+					Info: servicefile.Info{
+						Name:        "Orders",
+						Description: "Handles orders.",
+						Owner:       "team-orders",
+						Tags:        []string{"orders", "core"},
+					},
+					Relationships: []servicefile.Relationship{
+						{
+							Action:      servicefile.RelationshipActionRequests,
+							Participant: "Stripe",
+							Technology:  "stripe",
+							Proto:       "http",
+							External:    true,
+						},
+						{
+							Action:      servicefile.RelationshipActionSends,
+							Participant: "Kafka",
+							Description: "Publishes order events",
+							Technology:  "kafka",
+						},
+						{
+							Action:      servicefile.RelationshipActionUses,
+							Participant: "PostgreSQL",
+							Description: "Stores orders",
+							Technology:  "postgresql",
+							Proto:       "tcp",
+						},
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:        "parse typed annotations with non-literal arguments",
+			dir:         "testdata/invalid",
+			recursive:   true,
+			expectError: true,
+		},
 		{
 			name:        "parse mixed service relationships",
 			dir:         "testdata/mixed",
@@ -170,8 +268,8 @@ func TestParse(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			parser := NewCommentParser()
-			result, err := parser.Parse(tt.dir, tt.recursive, false)
+			cp := NewCommentParser()
+			result, err := cp.Parse(tt.dir, tt.recursive, false)
 
 			if tt.expectError {
 				if err == nil {
@@ -204,34 +302,34 @@ func TestParseFile(t *testing.T) {
 	tests := []struct {
 		name                  string
 		filePath              string
-		expectedServices      []service
-		expectedRelationships []relationship
+		expectedServices      []parser.Service
+		expectedRelationships []parser.Relationship
 		expectError           bool
 	}{
 		{
 			name:     "parse service file with comments",
 			filePath: "testdata/default/service/example/example.go",
-			expectedServices: []service{
+			expectedServices: []parser.Service{
 				{
-					name:        "Example",
-					description: "Example service for exampling stuff.",
+					Name:        "Example",
+					Description: "Example service for exampling stuff.",
 				},
 			},
-			expectedRelationships: []relationship{},
+			expectedRelationships: []parser.Relationship{},
 			expectError:           false,
 		},
 		{
 			name:             "parse file with relationship comments",
 			filePath:         "testdata/default/database/postgres/postgres.go",
-			expectedServices: []service{},
-			expectedRelationships: []relationship{
+			expectedServices: []parser.Service{},
+			expectedRelationships: []parser.Relationship{
 				{
-					serviceName: "",
-					action:      "uses",
-					targetName:  "PostgreSQL",
-					description: "Stores user data and authentication tokens",
-					technology:  "postgresql",
-					proto:       "tcp",
+					ServiceName: "",
+					Action:      "uses",
+					Participant: "PostgreSQL",
+					Description: "Stores user data and authentication tokens",
+					Technology:  "postgresql",
+					Proto:       "tcp",
 				},
 			},
 			expectError: false,
@@ -245,8 +343,8 @@ func TestParseFile(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			parser := NewCommentParser()
-			err := parser.parseFile(tt.filePath)
+			cp := NewCommentParser()
+			err := cp.parseFile(tt.filePath)
 
 			if tt.expectError {
 				if err == nil {
@@ -260,225 +358,12 @@ func TestParseFile(t *testing.T) {
 				return
 			}
 
-			if !compareServices(parser.services, tt.expectedServices) {
-				t.Errorf("parseFile() services = %+v, want %+v", parser.services, tt.expectedServices)
+			if !compareServices(cp.Services, tt.expectedServices) {
+				t.Errorf("parseFile() services = %+v, want %+v", cp.Services, tt.expectedServices)
 			}
 
-			if !compareRelationships(parser.relationships, tt.expectedRelationships) {
-				t.Errorf("parseFile() relationships = %+v, want %+v", parser.relationships, tt.expectedRelationships)
-			}
-		})
-	}
-}
-
-func TestParseCommentGroup(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		name                  string
-		commentGroup          string
-		expectedServices      []service
-		expectedRelationships []relationship
-	}{
-		{
-			name: "parse service info with all fields",
-			commentGroup: `/*
-service:name UserService
-description: Handles user authentication and profiles
-system: e-commerce-platform
-owner: team-auth
-repository: https://github.com/holydocs/servicefile
-tags: auth, user-management, microservice
-*/`,
-			expectedServices: []service{
-				{
-					name:        "UserService",
-					description: "Handles user authentication and profiles",
-					system:      "e-commerce-platform",
-					owner:       "team-auth",
-					repository:  "https://github.com/holydocs/servicefile",
-					tags:        []string{"auth", "user-management", "microservice"},
-				},
-			},
-			expectedRelationships: []relationship{},
-		},
-		{
-			name: "parse relationship with all fields",
-			commentGroup: `/*
-service:uses PostgreSQL
-description: Stores user data and authentication tokens
-technology:postgresql
-proto:tcp
-*/`,
-			expectedServices: []service{},
-			expectedRelationships: []relationship{
-				{
-					serviceName: "",
-					action:      "uses",
-					targetName:  "PostgreSQL",
-					description: "Stores user data and authentication tokens",
-					technology:  "postgresql",
-					proto:       "tcp",
-				},
-			},
-		},
-		{
-			name: "parse relationship with person attribute",
-			commentGroup: `/*
-service:replies User
-description: Replies to user requests via web interface
-technology:http
-person:true
-*/`,
-			expectedServices: []service{},
-			expectedRelationships: []relationship{
-				{
-					serviceName: "",
-					action:      "replies",
-					targetName:  "User",
-					description: "Replies to user requests via web interface",
-					technology:  "http",
-					person:      true,
-				},
-			},
-		},
-		{
-			name: "parse relationship with person attribute false",
-			commentGroup: `/*
-service:replies OtherService
-description: Replies to other service requests
-technology:grpc
-person:false
-*/`,
-			expectedServices: []service{},
-			expectedRelationships: []relationship{
-				{
-					serviceName: "",
-					action:      "replies",
-					targetName:  "OtherService",
-					description: "Replies to other service requests",
-					technology:  "grpc",
-					person:      false,
-				},
-			},
-		},
-		{
-			name:                  "parse empty comment group",
-			commentGroup:          `/* */`,
-			expectedServices:      []service{},
-			expectedRelationships: []relationship{},
-		},
-		{
-			name: "parse comments starting with //",
-			commentGroup: `// service:name Example
-// description: Example service for exampling stuff.`,
-			expectedServices: []service{
-				{
-					name:        "Example",
-					description: "Example service for exampling stuff.",
-				},
-			},
-			expectedRelationships: []relationship{},
-		},
-		{
-			name: "parse comments starting with // including owner",
-			commentGroup: `// service:name Example
-// description: Example service for exampling stuff.
-// owner: team-example`,
-			expectedServices: []service{
-				{
-					name:        "Example",
-					description: "Example service for exampling stuff.",
-					owner:       "team-example",
-				},
-			},
-			expectedRelationships: []relationship{},
-		},
-		{
-			name: "parse mixed comments: regular golang comments first, then service comments with /* */",
-			commentGroup: `// User represents a user in the system
-// This struct contains all user-related fields
-/*
-service:uses PostgreSQL
-description: Stores user data and authentication tokens
-technology:postgresql
-proto:tcp
-*/`,
-			expectedServices: []service{},
-			expectedRelationships: []relationship{
-				{
-					serviceName: "",
-					action:      "uses",
-					targetName:  "PostgreSQL",
-					description: "Stores user data and authentication tokens",
-					technology:  "postgresql",
-					proto:       "tcp",
-				},
-			},
-		},
-		{
-			name: "parse mixed comments: regular golang comments first, then service comments with //",
-			commentGroup: `// User represents a user in the system
-// This struct contains all user-related fields
-// service:uses PostgreSQL
-// description: Stores user data and authentication tokens
-// technology:postgresql
-// proto:tcp`,
-			expectedServices: []service{},
-			expectedRelationships: []relationship{
-				{
-					serviceName: "",
-					action:      "uses",
-					targetName:  "PostgreSQL",
-					description: "Stores user data and authentication tokens",
-					technology:  "postgresql",
-					proto:       "tcp",
-				},
-			},
-		},
-		{
-			name: "parse mixed comments: service comments with /* */ first, then regular golang comments",
-			commentGroup: `/*
-service:name Example
-description: Example service for exampling stuff.
-*/
-// User represents a user in the system
-// This struct contains all user-related fields`,
-			expectedServices: []service{
-				{
-					name:        "Example",
-					description: "Example service for exampling stuff.",
-				},
-			},
-			expectedRelationships: []relationship{},
-		},
-		{
-			name: "parse mixed comments: service comments with // first, then regular golang comments",
-			commentGroup: `// service:name Example
-// description: Example service for exampling stuff.
-// User represents a user in the system
-// This struct contains all user-related fields`,
-			expectedServices: []service{
-				{
-					name:        "Example",
-					description: "Example service for exampling stuff.",
-				},
-			},
-			expectedRelationships: []relationship{},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			parser := NewCommentParser()
-			parser.parseCommentGroup(tt.commentGroup)
-
-			if !compareServices(parser.services, tt.expectedServices) {
-				t.Errorf("parseCommentGroup() services = %+v, want %+v", parser.services, tt.expectedServices)
-			}
-
-			if !compareRelationships(parser.relationships, tt.expectedRelationships) {
-				t.Errorf("parseCommentGroup() relationships = %+v, want %+v", parser.relationships, tt.expectedRelationships)
+			if !compareRelationships(cp.Relationships, tt.expectedRelationships) {
+				t.Errorf("parseFile() relationships = %+v, want %+v", cp.Relationships, tt.expectedRelationships)
 			}
 		})
 	}
@@ -520,6 +405,10 @@ func compareServiceFiles(actual, expected map[string]*servicefile.ServiceFile) b
 			return false
 		}
 
+		if !reflect.DeepEqual(actualService.Info.Extensions, expectedService.Info.Extensions) {
+			return false
+		}
+
 		if len(actualService.Relationships) != len(expectedService.Relationships) {
 			return false
 		}
@@ -534,7 +423,11 @@ func compareServiceFiles(actual, expected map[string]*servicefile.ServiceFile) b
 					actualRel.Technology == expectedRel.Technology &&
 					actualRel.Proto == expectedRel.Proto &&
 					actualRel.External == expectedRel.External &&
-					actualRel.Person == expectedRel.Person {
+					actualRel.Person == expectedRel.Person &&
+					reflect.DeepEqual(actualRel.Channel, expectedRel.Channel) &&
+					compareStringSlices(actualRel.Operations, expectedRel.Operations) &&
+					actualRel.Spec == expectedRel.Spec &&
+					reflect.DeepEqual(actualRel.Extensions, expectedRel.Extensions) {
 					found = true
 					break
 				}
@@ -581,7 +474,7 @@ func compareStringSlices(actual, expected []string) bool {
 }
 
 // compareServices compares two service slices for equality
-func compareServices(actual, expected []service) bool {
+func compareServices(actual, expected []parser.Service) bool {
 	if len(actual) != len(expected) {
 		return false
 	}
@@ -590,12 +483,13 @@ func compareServices(actual, expected []service) bool {
 	for _, expectedService := range expected {
 		found := false
 		for _, actualService := range actual {
-			if actualService.name == expectedService.name &&
-				actualService.description == expectedService.description &&
-				actualService.system == expectedService.system &&
-				actualService.owner == expectedService.owner &&
-				actualService.repository == expectedService.repository &&
-				compareStringSlices(actualService.tags, expectedService.tags) {
+			if actualService.Name == expectedService.Name &&
+				actualService.Description == expectedService.Description &&
+				actualService.System == expectedService.System &&
+				actualService.Owner == expectedService.Owner &&
+				actualService.Repository == expectedService.Repository &&
+				compareStringSlices(actualService.Tags, expectedService.Tags) &&
+				reflect.DeepEqual(actualService.Extensions, expectedService.Extensions) {
 				found = true
 				break
 			}
@@ -609,7 +503,7 @@ func compareServices(actual, expected []service) bool {
 }
 
 // compareRelationships compares two relationship slices for equality
-func compareRelationships(actual, expected []relationship) bool {
+func compareRelationships(actual, expected []parser.Relationship) bool {
 	if len(actual) != len(expected) {
 		return false
 	}
@@ -618,14 +512,20 @@ func compareRelationships(actual, expected []relationship) bool {
 	for _, expectedRel := range expected {
 		found := false
 		for _, actualRel := range actual {
-			if actualRel.serviceName == expectedRel.serviceName &&
-				actualRel.action == expectedRel.action &&
-				actualRel.targetName == expectedRel.targetName &&
-				actualRel.technology == expectedRel.technology &&
-				actualRel.description == expectedRel.description &&
-				actualRel.proto == expectedRel.proto &&
-				actualRel.external == expectedRel.external &&
-				actualRel.person == expectedRel.person {
+			if actualRel.ServiceName == expectedRel.ServiceName &&
+				actualRel.Action == expectedRel.Action &&
+				actualRel.Participant == expectedRel.Participant &&
+				actualRel.Technology == expectedRel.Technology &&
+				actualRel.Description == expectedRel.Description &&
+				actualRel.Proto == expectedRel.Proto &&
+				actualRel.External == expectedRel.External &&
+				actualRel.Person == expectedRel.Person &&
+				actualRel.Channel == expectedRel.Channel &&
+				compareStringSlices(actualRel.Messages, expectedRel.Messages) &&
+				actualRel.Schema == expectedRel.Schema &&
+				compareStringSlices(actualRel.Operations, expectedRel.Operations) &&
+				actualRel.Spec == expectedRel.Spec &&
+				reflect.DeepEqual(actualRel.Extensions, expectedRel.Extensions) {
 				found = true
 				break
 			}
@@ -638,46 +538,46 @@ func compareRelationships(actual, expected []relationship) bool {
 	return true
 }
 
-func TestMakeGitRepositoryURL(t *testing.T) {
+func TestImplementedGRPCService(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name     string
-		input    string
+		src      string
 		expected string
 	}{
 		{
-			name:     "git@github.com format",
-			input:    "git@github.com:holydocs/servicefile.git",
-			expected: "https://github.com/holydocs/servicefile",
-		},
-		{
-			name:     "https://github.com format with .git suffix",
-			input:    "https://github.com/holydocs/servicefile.git",
-			expected: "https://github.com/holydocs/servicefile",
+			name:     "embedded from generated package",
+			src:      "type Server struct {\n\tuserv1.UnimplementedUserServiceServer\n}",
+			expected: "UserService",
 		},
 		{
-			name:     "http://github.com format with .git suffix",
-			input:    "http://github.com/holydocs/servicefile.git",
-			expected: "http://github.com/holydocs/servicefile",
+			name:     "embedded pointer from same package",
+			src:      "type Server struct {\n\t*UnimplementedOrdersServer\n}",
+			expected: "Orders",
 		},
 		{
-			name:     "complex nested path",
-			input:    "git@github.com:org/team/subteam/project.git",
-			expected: "https://github.com/org/team/subteam/project",
+			name: "named field",
+			src:  "type Server struct {\n\tsrv UnimplementedUserServiceServer\n}",
 		},
 		{
-			name:     "with multiple dots in path",
-			input:    "git@github.com:user/my-project.v2.git",
-			expected: "https://github.com/user/my-project.v2",
+			name: "not a struct",
+			src:  "type Server interface{}",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := makeGitRepositoryURL(tt.input)
-			if result != tt.expected {
-				t.Errorf("makeGitRepositoryURL(%q) = %q, want %q", tt.input, result, tt.expected)
+			t.Parallel()
+
+			f, err := goparser.ParseFile(token.NewFileSet(), "server.go", "package server\n\n"+tt.src, 0)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			ts := f.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec)
+			if name := implementedGRPCService(ts); name != tt.expected {
+				t.Errorf("implementedGRPCService() = %q, want %q", name, tt.expected)
 			}
 		})
 	}
@@ -0,0 +1,619 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/holydocs/servicefile/internal/parser"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+// FindingKind represents a kind of lint finding.
+type FindingKind string
+
+const (
+	// FindingKindMissing reports a dependency evident from the code that has no annotation.
+	FindingKindMissing FindingKind = "missing"
+	// FindingKindStale reports an annotation that has no matching dependency in the code.
+	FindingKindStale FindingKind = "stale"
+)
+
+// ParticipantPlaceholder is the participant of missing relationships whose client doesn't
+// reveal the service it talks to, e.g. gRPC and HTTP clients.
+const ParticipantPlaceholder = "<service>"
+
+// Finding represents a mismatch between the code and its annotations.
+type Finding struct {
+	Kind         FindingKind
+	File         string
+	Type         string
+	Relationship servicefile.Relationship
+	Reason       string
+	// Service is the name of the service owning the file of a missing relationship
+	// when relationships name their service, empty otherwise.
+	Service string
+}
+
+func (f Finding) String() string {
+	target := f.File
+	if f.Type != "" {
+		target = fmt.Sprintf("%s (%s)", f.File, f.Type)
+	}
+
+	return fmt.Sprintf("%s: %s: %s %s: %s", f.Kind, target, f.Relationship.Action, f.Relationship.Technology, f.Reason)
+}
+
+// knownClient describes a package whose usage reveals a relationship.
+type knownClient struct {
+	importPath  string
+	participant string
+	technology  string
+	proto       string
+	// action is used when the package import alone is enough to reveal the relationship.
+	action string
+	// constructors maps constructor function names to the action they reveal.
+	constructors map[string]string
+}
+
+var knownClients = []knownClient{
+	{importPath: "github.com/lib/pq", participant: "PostgreSQL", technology: "postgresql", proto: "tcp", action: servicefile.RelationshipActionUses},
+	{importPath: "github.com/jackc/pgx", participant: "PostgreSQL", technology: "postgresql", proto: "tcp", action: servicefile.RelationshipActionUses},
+	{importPath: "github.com/go-sql-driver/mysql", participant: "MySQL", technology: "mysql", proto: "tcp", action: servicefile.RelationshipActionUses},
+	{importPath: "github.com/mattn/go-sqlite3", participant: "SQLite", technology: "sqlite", action: servicefile.RelationshipActionUses},
+	{importPath: "go.mongodb.org/mongo-driver", participant: "MongoDB", technology: "mongodb", proto: "tcp", action: servicefile.RelationshipActionUses},
+	{importPath: "github.com/redis/go-redis", participant: "Redis", technology: "redis", proto: "tcp", action: servicefile.RelationshipActionUses},
+	{importPath: "github.com/go-redis/redis", participant: "Redis", technology: "redis", proto: "tcp", action: servicefile.RelationshipActionUses},
+	{importPath: "github.com/gomodule/redigo", participant: "Redis", technology: "redis", proto: "tcp", action: servicefile.RelationshipActionUses},
+	{importPath: "github.com/elastic/go-elasticsearch", participant: "Elasticsearch", technology: "elasticsearch", proto: "http", action: servicefile.RelationshipActionUses},
+	{importPath: "github.com/aws/aws-sdk-go-v2/service/s3", participant: "S3", technology: "s3", proto: "http", action: servicefile.RelationshipActionUses},
+	{
+		importPath:  "github.com/segmentio/kafka-go",
+		participant: "Kafka",
+		technology:  "kafka",
+		proto:       "tcp",
+		constructors: map[string]string{
+			"NewWriter": servicefile.RelationshipActionSends,
+			"Writer":    servicefile.RelationshipActionSends,
+			"NewReader": servicefile.RelationshipActionReceives,
+		},
+	},
+	{
+		importPath:  "github.com/IBM/sarama",
+		participant: "Kafka",
+		technology:  "kafka",
+		proto:       "tcp",
+		constructors: map[string]string{
+			"NewSyncProducer":  servicefile.RelationshipActionSends,
+			"NewAsyncProducer": servicefile.RelationshipActionSends,
+			"NewConsumer":      servicefile.RelationshipActionReceives,
+			"NewConsumerGroup": servicefile.RelationshipActionReceives,
+		},
+	},
+	{
+		importPath:  "github.com/confluentinc/confluent-kafka-go",
+		participant: "Kafka",
+		technology:  "kafka",
+		proto:       "tcp",
+		constructors: map[string]string{
+			"NewProducer": servicefile.RelationshipActionSends,
+			"NewConsumer": servicefile.RelationshipActionReceives,
+		},
+	},
+	{importPath: "github.com/rabbitmq/amqp091-go", participant: "RabbitMQ", technology: "rabbitmq", proto: "amqp", action: servicefile.RelationshipActionSends},
+	{importPath: "github.com/streadway/amqp", participant: "RabbitMQ", technology: "rabbitmq", proto: "amqp", action: servicefile.RelationshipActionSends},
+	{importPath: "github.com/nats-io/nats.go", participant: "NATS", technology: "nats", proto: "tcp", action: servicefile.RelationshipActionSends},
+	{
+		importPath: "google.golang.org/grpc",
+		technology: "grpc",
+		proto:      "grpc",
+		constructors: map[string]string{
+			"Dial":        servicefile.RelationshipActionRequests,
+			"DialContext": servicefile.RelationshipActionRequests,
+			"NewClient":   servicefile.RelationshipActionRequests,
+		},
+	},
+	{
+		importPath: "net/http",
+		technology: "http",
+		proto:      "http",
+		constructors: map[string]string{
+			"Get":                   servicefile.RelationshipActionRequests,
+			"Post":                  servicefile.RelationshipActionRequests,
+			"PostForm":              servicefile.RelationshipActionRequests,
+			"Head":                  servicefile.RelationshipActionRequests,
+			"NewRequest":            servicefile.RelationshipActionRequests,
+			"NewRequestWithContext": servicefile.RelationshipActionRequests,
+			"Client":                servicefile.RelationshipActionRequests,
+		},
+	},
+}
+
+// asyncActions are interchangeable when matching broker clients with annotations,
+// since the same client is commonly used for both directions.
+var asyncActions = map[string]bool{
+	servicefile.RelationshipActionSends:    true,
+	servicefile.RelationshipActionReceives: true,
+}
+
+// technologyAliases normalizes technology names commonly used in annotations.
+var technologyAliases = map[string]string{
+	"postgres":    "postgresql",
+	"pg":          "postgresql",
+	"mongo":       "mongodb",
+	"amqp":        "rabbitmq",
+	"grpc-client": "grpc",
+	"http-client": "http",
+	"https":       "http",
+}
+
+// dependency represents a relationship evident from the code itself.
+type dependency struct {
+	client   knownClient
+	action   string
+	location parser.Location
+}
+
+// Lint compares dependencies evident from the code in dir with the relationships
+// declared in annotations and reports the missing and stale ones.
+func (cp *CommentParser) Lint(dir string, recursive bool) ([]Finding, error) {
+	var deps []dependency
+
+	err := walkGoFiles(dir, recursive, func(path string) error {
+		fset := token.NewFileSet()
+
+		f, err := goparser.ParseFile(fset, path, nil, goparser.ParseComments)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		if err := parseAST(path, f, fset, cp.Annotations); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		deps = append(deps, detectDependencies(path, f)...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cp.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cp.lint(deps), nil
+}
+
+func (cp *CommentParser) lint(deps []dependency) []Finding {
+	var findings []Finding
+
+	reported := make(map[string]bool)
+
+	for _, dep := range deps {
+		if cp.isDeclared(dep) {
+			continue
+		}
+
+		key := dep.location.File + "|" + dep.action + "|" + dep.client.technology
+		if reported[key] {
+			continue
+		}
+		reported[key] = true
+
+		participant := dep.client.participant
+		if participant == "" {
+			participant = ParticipantPlaceholder
+		}
+
+		findings = append(findings, Finding{
+			Kind:    FindingKindMissing,
+			File:    dep.location.File,
+			Type:    dep.location.TypeName,
+			Service: cp.serviceOf(dep.location.File),
+			Relationship: servicefile.Relationship{
+				Action:      servicefile.RelationshipAction(dep.action),
+				Participant: participant,
+				Technology:  dep.client.technology,
+				Proto:       dep.client.proto,
+			},
+			Reason: fmt.Sprintf("%s is used but no relationship is declared", dep.client.importPath),
+		})
+	}
+
+	for _, r := range cp.Relationships {
+		if !isDetectable(r) || cp.isDetected(r, deps) {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Kind: FindingKindStale,
+			File: r.Location.File,
+			Type: r.Location.TypeName,
+			Relationship: servicefile.Relationship{
+				Action:      servicefile.RelationshipAction(r.Action),
+				Participant: r.Participant,
+				Technology:  r.Technology,
+				Proto:       r.Proto,
+			},
+			Reason: "relationship is declared but no matching client is used",
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+
+		return findings[i].Kind < findings[j].Kind
+	})
+
+	return findings
+}
+
+// isDeclared reports whether a relationship of the service owning the file of the dependency declares it.
+func (cp *CommentParser) isDeclared(dep dependency) bool {
+	owners := cp.ownersOf(dep.location.File)
+
+	for _, r := range cp.Relationships {
+		if owners != nil && !owners[r.ServiceName] {
+			continue
+		}
+
+		if actionsMatch(r.Action, dep.action) && normalizeTechnology(r.Technology) == dep.client.technology {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ownersOf returns the names of the services owning the file: the services of the explicit
+// relationships declared in the closest directory holding the file. It returns nil, standing
+// for any service, when no such relationship is found, e.g. for the implicit pattern, where
+// all relationships belong to a single service.
+func (cp *CommentParser) ownersOf(file string) map[string]bool {
+	var (
+		owners  map[string]bool
+		closest string
+	)
+
+	dir := filepath.Dir(file)
+
+	for _, r := range cp.Relationships {
+		relDir := filepath.Dir(r.Location.File)
+		if r.ServiceName == "" || !isWithin(dir, relDir) {
+			continue
+		}
+
+		switch {
+		case owners == nil || len(relDir) > len(closest):
+			owners, closest = map[string]bool{r.ServiceName: true}, relDir
+		case relDir == closest:
+			owners[r.ServiceName] = true
+		}
+	}
+
+	return owners
+}
+
+// serviceOf returns the name of the only service owning the file, if any.
+func (cp *CommentParser) serviceOf(file string) string {
+	owners := cp.ownersOf(file)
+	if len(owners) != 1 {
+		return ""
+	}
+
+	for name := range owners {
+		return name
+	}
+
+	return ""
+}
+
+// isWithin reports whether dir is parent or one of its subdirectories.
+func isWithin(dir, parent string) bool {
+	return dir == parent || parent == "." || strings.HasPrefix(dir, parent+string(filepath.Separator))
+}
+
+// isDetectable reports whether the relationship is of a kind that detectDependencies can find.
+func isDetectable(r parser.Relationship) bool {
+	technology := normalizeTechnology(r.Technology)
+
+	for _, c := range knownClients {
+		if c.technology != technology {
+			continue
+		}
+
+		if c.action != "" && actionsMatch(r.Action, c.action) {
+			return true
+		}
+
+		for _, action := range c.constructors {
+			if actionsMatch(r.Action, action) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isDetected reports whether a dependency of a file owned by the service of the relationship matches it.
+func (cp *CommentParser) isDetected(r parser.Relationship, deps []dependency) bool {
+	for _, dep := range deps {
+		if owners := cp.ownersOf(dep.location.File); owners != nil && !owners[r.ServiceName] {
+			continue
+		}
+
+		if actionsMatch(r.Action, dep.action) && normalizeTechnology(r.Technology) == dep.client.technology {
+			return true
+		}
+	}
+
+	return false
+}
+
+func actionsMatch(declared, detected string) bool {
+	return declared == detected || (asyncActions[declared] && asyncActions[detected])
+}
+
+func normalizeTechnology(technology string) string {
+	technology = strings.ToLower(strings.TrimSpace(technology))
+	if alias, ok := technologyAliases[technology]; ok {
+		return alias
+	}
+
+	return technology
+}
+
+// detectDependencies finds imports and constructor calls of known clients in a file.
+func detectDependencies(path string, f *ast.File) []dependency {
+	imported := make(map[string]knownClient)
+
+	for _, imp := range f.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		client, ok := lookupKnownClient(importPath)
+		if !ok {
+			continue
+		}
+
+		name := importName(importPath)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+
+		if name == "_" {
+			// Blank imports register database drivers, the relationship belongs to the file.
+			if client.action != "" {
+				imported[importPath] = client
+			}
+			continue
+		}
+
+		imported[name] = client
+	}
+
+	if len(imported) == 0 {
+		return nil
+	}
+
+	var (
+		deps  []dependency
+		found = make(map[string]bool)
+	)
+
+	addDependency := func(client knownClient, action, typeName string) {
+		key := client.importPath + "|" + action
+		if found[key] {
+			return
+		}
+		found[key] = true
+
+		deps = append(deps, dependency{
+			client:   client,
+			action:   action,
+			location: parser.Location{File: path, TypeName: typeName},
+		})
+	}
+
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		typeName := funcTypeName(fn, f)
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			var expr ast.Expr
+
+			switch x := n.(type) {
+			case *ast.CallExpr:
+				expr = x.Fun
+			case *ast.CompositeLit:
+				expr = x.Type
+			default:
+				return true
+			}
+
+			sel, ok := expr.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			ident, ok := sel.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+
+			client, ok := imported[ident.Name]
+			if !ok {
+				return true
+			}
+
+			if action, ok := client.constructors[sel.Sel.Name]; ok {
+				addDependency(client, action, typeName)
+			}
+
+			return true
+		})
+	}
+
+	for name, client := range imported {
+		if client.action == "" {
+			continue
+		}
+
+		addDependency(client, client.action, fieldTypeName(f, name))
+	}
+
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].client.importPath != deps[j].client.importPath {
+			return deps[i].client.importPath < deps[j].client.importPath
+		}
+
+		return deps[i].action < deps[j].action
+	})
+
+	return deps
+}
+
+func lookupKnownClient(importPath string) (knownClient, bool) {
+	for _, c := range knownClients {
+		if importPath == c.importPath || strings.HasPrefix(importPath, c.importPath+"/") {
+			return c, true
+		}
+	}
+
+	return knownClient{}, false
+}
+
+// importName returns the default package name for an import path.
+func importName(importPath string) string {
+	parts := strings.Split(importPath, "/")
+	name := parts[len(parts)-1]
+
+	if len(parts) > 1 && strings.HasPrefix(name, "v") {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			name = parts[len(parts)-2]
+		}
+	}
+
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	name = strings.TrimSuffix(name, ".go")
+
+	return strings.ReplaceAll(name, "-", "")
+}
+
+// funcTypeName returns the type a function belongs to: its receiver or,
+// for constructors, the type it returns.
+func funcTypeName(fn *ast.FuncDecl, f *ast.File) string {
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		return baseTypeName(fn.Recv.List[0].Type)
+	}
+
+	if fn.Type.Results != nil && len(fn.Type.Results.List) > 0 {
+		name := baseTypeName(fn.Type.Results.List[0].Type)
+		if name != "" && declaresType(f, name) {
+			return name
+		}
+	}
+
+	return fieldTypeName(f, "")
+}
+
+// fieldTypeName returns the first struct type in the file with a field of a type
+// from the given package, falling back to the first type declared in the file.
+func fieldTypeName(f *ast.File, pkg string) string {
+	var first string
+
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if first == "" {
+				first = ts.Name.Name
+			}
+
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || pkg == "" {
+				continue
+			}
+
+			for _, field := range st.Fields.List {
+				if referencesPackage(field.Type, pkg) {
+					return ts.Name.Name
+				}
+			}
+		}
+	}
+
+	return first
+}
+
+func referencesPackage(expr ast.Expr, pkg string) bool {
+	found := false
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == pkg {
+			found = true
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+func baseTypeName(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.StarExpr:
+		return baseTypeName(x.X)
+	case *ast.IndexExpr:
+		return baseTypeName(x.X)
+	case *ast.IndexListExpr:
+		return baseTypeName(x.X)
+	}
+
+	return ""
+}
+
+func declaresType(f *ast.File, name string) bool {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			if spec.(*ast.TypeSpec).Name.Name == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
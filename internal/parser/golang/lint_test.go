@@ -0,0 +1,136 @@
+package golang
+
+import (
+	"testing"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		dir      string
+		expected []Finding
+	}{
+		{
+			name: "implicit",
+			dir:  "testdata/lint",
+			expected: []Finding{
+				{
+					Kind: FindingKindMissing,
+					File: "testdata/lint/events/events.go",
+					Type: "Publisher",
+					Relationship: servicefile.Relationship{
+						Action:      servicefile.RelationshipActionSends,
+						Participant: "Kafka",
+						Technology:  "kafka",
+						Proto:       "tcp",
+					},
+				},
+				{
+					Kind: FindingKindStale,
+					File: "testdata/lint/storage/storage.go",
+					Type: "Cache",
+					Relationship: servicefile.Relationship{
+						Action:      servicefile.RelationshipActionUses,
+						Participant: "Redis",
+						Technology:  "redis",
+					},
+				},
+			},
+		},
+		{
+			name: "relationships of other services",
+			dir:  "testdata/lintservices",
+			expected: []Finding{
+				{
+					Kind:    FindingKindMissing,
+					File:    "testdata/lintservices/services/billing/billing.go",
+					Type:    "Server",
+					Service: "billing",
+					Relationship: servicefile.Relationship{
+						Action:      servicefile.RelationshipActionUses,
+						Participant: "PostgreSQL",
+						Technology:  "postgresql",
+						Proto:       "tcp",
+					},
+				},
+				{
+					Kind:    FindingKindMissing,
+					File:    "testdata/lintservices/services/billing/billing.go",
+					Type:    "Server",
+					Service: "billing",
+					Relationship: servicefile.Relationship{
+						Action:      servicefile.RelationshipActionRequests,
+						Participant: ParticipantPlaceholder,
+						Technology:  "grpc",
+						Proto:       "grpc",
+					},
+				},
+				{
+					Kind: FindingKindStale,
+					File: "testdata/lintservices/services/shipping/shipping.go",
+					Type: "Repository",
+					Relationship: servicefile.Relationship{
+						Action:      servicefile.RelationshipActionUses,
+						Participant: "PostgreSQL",
+						Technology:  "postgresql",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			findings, err := NewCommentParser().Lint(tt.dir, true)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(findings) != len(tt.expected) {
+				t.Fatalf("Lint() = %+v, want %+v", findings, tt.expected)
+			}
+
+			for i, f := range findings {
+				f.Reason = ""
+				if f.Kind != tt.expected[i].Kind || f.File != tt.expected[i].File || f.Type != tt.expected[i].Type ||
+					f.Service != tt.expected[i].Service ||
+					f.Relationship.Action != tt.expected[i].Relationship.Action ||
+					f.Relationship.Participant != tt.expected[i].Relationship.Participant ||
+					f.Relationship.Technology != tt.expected[i].Relationship.Technology ||
+					f.Relationship.Proto != tt.expected[i].Relationship.Proto {
+					t.Errorf("Lint()[%d] = %+v, want %+v", i, f, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestImportName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "github.com/segmentio/kafka-go", expected: "kafka"},
+		{input: "github.com/redis/go-redis/v9", expected: "redis"},
+		{input: "github.com/jackc/pgx/v5/pgxpool", expected: "pgxpool"},
+		{input: "github.com/nats-io/nats.go", expected: "nats"},
+		{input: "github.com/rabbitmq/amqp091-go", expected: "amqp091"},
+		{input: "net/http", expected: "http"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := importName(tt.input); result != tt.expected {
+				t.Errorf("importName(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+/*
+service:name Users
+description: Manages users
+*/
+package main
+
+func main() {}
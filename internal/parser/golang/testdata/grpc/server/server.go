@@ -0,0 +1,11 @@
+package server
+
+import userv1 "example.com/users/gen/user/v1"
+
+/*
+service:replies
+description: Provides user management APIs
+*/
+type Server struct {
+	userv1.UnimplementedUserServiceServer
+}
@@ -0,0 +1,7 @@
+package invalid
+
+import "github.com/holydocs/servicefile/pkg/servicefile"
+
+const participant = "PostgreSQL"
+
+var _ = servicefile.Uses(participant, servicefile.Technology("postgresql"))
@@ -0,0 +1,15 @@
+package events
+
+import (
+	"github.com/segmentio/kafka-go"
+)
+
+type Publisher struct {
+	w *kafka.Writer
+}
+
+func NewPublisher(brokers []string) *Publisher {
+	return &Publisher{
+		w: kafka.NewWriter(kafka.WriterConfig{Brokers: brokers, Topic: "orders"}),
+	}
+}
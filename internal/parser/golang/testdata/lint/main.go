@@ -0,0 +1,7 @@
+/*
+service:name Orders
+description: Handles orders.
+*/
+package main
+
+func main() {}
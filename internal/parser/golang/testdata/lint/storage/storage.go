@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+service:uses PostgreSQL
+description: Stores orders
+technology:postgres
+proto:tcp
+*/
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(ctx context.Context, dsn string) (*Repository, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repository{pool: pool}, nil
+}
+
+/*
+service:uses Redis
+description: Caches orders
+technology:redis
+*/
+type Cache struct{}
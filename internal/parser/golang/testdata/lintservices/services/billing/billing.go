@@ -0,0 +1,32 @@
+package billing
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
+)
+
+/*
+service:billing:replies Customer
+description: Serves invoices
+technology:http-server
+*/
+type Server struct {
+	pool   *pgxpool.Pool
+	ledger *grpc.ClientConn
+}
+
+func NewServer(ctx context.Context, dsn, ledger string) (*Server, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(ledger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{pool: pool, ledger: conn}, nil
+}
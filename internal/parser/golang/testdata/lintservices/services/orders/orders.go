@@ -0,0 +1,25 @@
+package orders
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+service:orders:uses PostgreSQL
+description: Stores orders
+technology:postgresql
+*/
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(ctx context.Context, dsn string) (*Repository, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repository{pool: pool}, nil
+}
@@ -0,0 +1,8 @@
+package shipping
+
+/*
+service:shipping:uses PostgreSQL
+description: Stores shipments
+technology:postgresql
+*/
+type Repository struct{}
@@ -0,0 +1,8 @@
+package api
+
+/*
+service:replies
+spec: openapi.yaml
+operation: get /health
+*/
+type Server struct{}
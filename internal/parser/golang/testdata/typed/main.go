@@ -0,0 +1,11 @@
+package main
+
+import "github.com/holydocs/servicefile/pkg/servicefile"
+
+var _ = servicefile.Service("Orders",
+	servicefile.Description("Handles orders."),
+	servicefile.Owner("team-orders"),
+	servicefile.Tags("orders", "core"),
+)
+
+func main() {}
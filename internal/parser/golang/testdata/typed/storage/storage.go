@@ -0,0 +1,23 @@
+package storage
+
+import sf "github.com/holydocs/servicefile/pkg/servicefile"
+
+var (
+	_ = sf.Uses("PostgreSQL",
+		sf.Description("Stores orders"),
+		sf.Technology("postgresql"),
+		sf.Proto("tcp"),
+	)
+	_ = sf.Requests("Stripe",
+		sf.Technology("stripe"),
+		sf.Proto("http"),
+		sf.External(),
+	)
+)
+
+/*
+service:sends Kafka
+description: Publishes order events
+technology:kafka
+*/
+type Publisher struct{}
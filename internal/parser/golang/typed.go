@@ -0,0 +1,243 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"github.com/holydocs/servicefile/internal/parser"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+const servicefileImportPath = "github.com/holydocs/servicefile/pkg/servicefile"
+
+var typedActions = map[string]string{
+	"Uses":     servicefile.RelationshipActionUses,
+	"Requests": servicefile.RelationshipActionRequests,
+	"Replies":  servicefile.RelationshipActionReplies,
+	"Sends":    servicefile.RelationshipActionSends,
+	"Receives": servicefile.RelationshipActionReceives,
+}
+
+// parseTypedAnnotations parses package-level declarations built with the
+// servicefile package helpers, e.g. var _ = servicefile.Uses("PostgreSQL").
+func parseTypedAnnotations(path string, f *ast.File, fset *token.FileSet, a *parser.Annotations) error {
+	pkg := servicefileImportName(f)
+	if pkg == "" {
+		return nil
+	}
+
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			for _, value := range spec.(*ast.ValueSpec).Values {
+				call, name, ok := selectorCall(value, pkg)
+				if !ok {
+					continue
+				}
+
+				var err error
+
+				switch {
+				case name == "Service":
+					err = parseTypedService(call, pkg, a)
+				case typedActions[name] != "":
+					err = parseTypedRelationship(call, pkg, typedActions[name], parser.Location{File: path}, a)
+				default:
+					continue
+				}
+
+				if err != nil {
+					return &parser.AnnotationError{Position: fset.Position(call.Pos()).String(), Err: err}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseTypedService(call *ast.CallExpr, pkg string, a *parser.Annotations) error {
+	if len(call.Args) == 0 {
+		return fmt.Errorf("service name is required")
+	}
+
+	name, err := stringLiteral(call.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid service name: %w", err)
+	}
+
+	s := parser.Service{Name: name}
+
+	for _, arg := range call.Args[1:] {
+		opt, name, ok := selectorCall(arg, pkg)
+		if !ok {
+			return fmt.Errorf("unsupported option %s", describeExpr(arg))
+		}
+
+		values, err := stringLiterals(opt.Args)
+		if err != nil {
+			return fmt.Errorf("invalid %s option: %w", name, err)
+		}
+
+		switch {
+		case name == "Description" && len(values) == 1:
+			s.Description = values[0]
+		case name == "System" && len(values) == 1:
+			s.System = values[0]
+		case name == "Owner" && len(values) == 1:
+			s.Owner = values[0]
+		case name == "Repository" && len(values) == 1:
+			s.Repository = values[0]
+		case name == "Tags":
+			s.Tags = values
+		case name == "Extension" && len(values) == 2 && servicefile.IsExtension(values[0]):
+			s.Extensions = parser.SetExtension(s.Extensions, values[0], parser.ExtensionValue(values[1]))
+		default:
+			return fmt.Errorf("unsupported service option %s", name)
+		}
+	}
+
+	a.Services = append(a.Services, s)
+
+	return nil
+}
+
+func parseTypedRelationship(call *ast.CallExpr, pkg, action string, loc parser.Location, a *parser.Annotations) error {
+	if len(call.Args) == 0 {
+		return fmt.Errorf("participant is required")
+	}
+
+	participant, err := stringLiteral(call.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid participant: %w", err)
+	}
+
+	r := parser.Relationship{
+		Action:      action,
+		Participant: participant,
+		Location:    loc,
+	}
+
+	for _, arg := range call.Args[1:] {
+		opt, name, ok := selectorCall(arg, pkg)
+		if !ok {
+			return fmt.Errorf("unsupported option %s", describeExpr(arg))
+		}
+
+		values, err := stringLiterals(opt.Args)
+		if err != nil {
+			return fmt.Errorf("invalid %s option: %w", name, err)
+		}
+
+		switch {
+		case name == "For" && len(values) == 1:
+			r.ServiceName = values[0]
+		case name == "Description" && len(values) == 1:
+			r.Description = values[0]
+		case name == "Technology" && len(values) == 1:
+			r.Technology = values[0]
+		case name == "Proto" && len(values) == 1:
+			r.Proto = values[0]
+		case name == "Tags":
+			r.Tags = values
+		case name == "External" && len(values) == 0:
+			r.External = true
+		case name == "Person" && len(values) == 0:
+			r.Person = true
+		case name == "ChannelName" && len(values) == 1:
+			r.Channel = values[0]
+		case name == "Messages":
+			r.Messages = values
+		case name == "Schema" && len(values) == 1:
+			r.Schema = values[0]
+		case name == "Operations":
+			for _, value := range values {
+				r.Operations = append(r.Operations, servicefile.NormalizeOperation(value))
+			}
+		case name == "Spec" && len(values) == 1:
+			r.Spec = values[0]
+		case name == "Extension" && len(values) == 2 && servicefile.IsExtension(values[0]):
+			r.Extensions = parser.SetExtension(r.Extensions, values[0], parser.ExtensionValue(values[1]))
+		default:
+			return fmt.Errorf("unsupported relationship option %s", name)
+		}
+	}
+
+	a.Relationships = append(a.Relationships, r)
+
+	return nil
+}
+
+// servicefileImportName returns the name the servicefile package is imported with.
+func servicefileImportName(f *ast.File) string {
+	for _, imp := range f.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || importPath != servicefileImportPath {
+			continue
+		}
+
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+
+		return "servicefile"
+	}
+
+	return ""
+}
+
+// selectorCall matches calls of functions from the pkg package.
+func selectorCall(expr ast.Expr, pkg string) (*ast.CallExpr, string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil, "", false
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, "", false
+	}
+
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok || ident.Name != pkg {
+		return nil, "", false
+	}
+
+	return call, sel.Sel.Name, true
+}
+
+func stringLiteral(expr ast.Expr) (string, error) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", fmt.Errorf("expected string literal, got %s", describeExpr(expr))
+	}
+
+	return strconv.Unquote(lit.Value)
+}
+
+func stringLiterals(exprs []ast.Expr) ([]string, error) {
+	values := make([]string, 0, len(exprs))
+
+	for _, expr := range exprs {
+		value, err := stringLiteral(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// describeExpr returns the source of an expression for error messages.
+func describeExpr(expr ast.Expr) string {
+	return types.ExprString(expr)
+}
@@ -0,0 +1,100 @@
+package jvm
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/holydocs/servicefile/pkg/language"
+)
+
+func init() {
+	language.MustRegister(NewJavaParser())
+	language.MustRegister(NewKotlinParser())
+}
+
+// Parser collects annotations from the comments of Java or Kotlin source code.
+type Parser struct {
+	name       string
+	extensions []string
+	syntax     *language.Syntax
+}
+
+// NewJavaParser creates a parser of Java source files.
+func NewJavaParser() *Parser {
+	return &Parser{
+		name:       "java",
+		extensions: []string{".java"},
+		syntax:     newSyntax(false),
+	}
+}
+
+// NewKotlinParser creates a parser of Kotlin source and script files.
+func NewKotlinParser() *Parser {
+	return &Parser{
+		name:       "kotlin",
+		extensions: []string{".kt", ".kts"},
+		syntax:     newSyntax(true),
+	}
+}
+
+// newSyntax returns the syntax of Java comments, or of Kotlin comments, where block comments
+// may contain other block comments and escape sequences aren't processed in """ strings.
+func newSyntax(kotlin bool) *language.Syntax {
+	return &language.Syntax{
+		LineComment:  "//",
+		BlockStart:   "/*",
+		BlockEnd:     "*/",
+		NestedBlocks: kotlin,
+		Literal: func(src string, i int) int {
+			switch {
+			case strings.HasPrefix(src[i:], `"""`):
+				return skipTextBlock(src, i, !kotlin)
+			case src[i] == '"' || src[i] == '\'':
+				return language.SkipString(src, i)
+			}
+
+			return i
+		},
+		Declaration: declarationPattern,
+	}
+}
+
+// Name returns the name of the language.
+func (p *Parser) Name() string {
+	return p.name
+}
+
+// Extensions returns the extensions of the source files of the language.
+func (p *Parser) Extensions() []string {
+	return p.extensions
+}
+
+// Comments returns the comments of a source file.
+func (p *Parser) Comments(_ string, src []byte) ([]language.Comment, error) {
+	return p.syntax.Comments(string(src)), nil
+}
+
+// skipTextBlock returns the index following the """ string starting at i.
+func skipTextBlock(src string, i int, escapes bool) int {
+	for i += 3; i < len(src); i++ {
+		switch {
+		case escapes && src[i] == '\\':
+			i++
+		case strings.HasPrefix(src[i:], `"""`):
+			// Kotlin raw strings may end with quotes, e.g. """a""""
+			i += 3
+			for i < len(src) && src[i] == '"' {
+				i++
+			}
+
+			return i
+		}
+	}
+
+	return i
+}
+
+var declarationPattern = regexp.MustCompile(`^\s*(?:@[\w.]+(?:\([^)]*\))?\s*)*` +
+	`(?:(?:public|protected|private|internal|static|final|abstract|open|sealed|data|inner|enum|annotation|` +
+	`value|companion|override|suspend|inline|strictfp)\s+)*` +
+	`(?:class|interface|@interface|enum|record|object|fun|val|var)\s+([A-Za-z_$][\w$]*)`)
@@ -0,0 +1,211 @@
+package jvm
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/holydocs/servicefile/internal/parser"
+	"github.com/holydocs/servicefile/pkg/language"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+// TestParse parses Java and Kotlin trees equivalent to the default and explicit testdata of the Go parser.
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	languages := []*Parser{NewJavaParser(), NewKotlinParser()}
+
+	tests := []struct {
+		name     string
+		dir      string
+		expected []*servicefile.ServiceFile
+	}{
+		{
+			name: "default",
+			dir:  "default",
+			expected: []*servicefile.ServiceFile{
+				{
+					Info: servicefile.Info{
+						Name:        "Example",
+						Description: "Example service for exampling stuff.",
+					},
+					Relationships: []servicefile.Relationship{
+						{
+							Action:      servicefile.RelationshipActionReplies,
+							Description: "Provides user management APIs to other services",
+							Technology:  "grpc-server",
+							Proto:       "grpc",
+						},
+						{
+							Action:      servicefile.RelationshipActionRequests,
+							Participant: "Firebase",
+							Description: "Handles push notifications",
+							Technology:  "firebase",
+							Proto:       "http",
+						},
+						{
+							Action:      servicefile.RelationshipActionUses,
+							Participant: "PostgreSQL",
+							Description: "Stores user data and authentication tokens",
+							Technology:  "postgresql",
+							Proto:       "tcp",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "explicit",
+			dir:  "explicit",
+			expected: []*servicefile.ServiceFile{
+				{
+					Info: servicefile.Info{
+						Name:        "auth",
+						Description: "Authentication service that handles user authentication and authorization",
+					},
+					Relationships: []servicefile.Relationship{
+						{
+							Action:      servicefile.RelationshipActionReplies,
+							Participant: "user",
+							Description: "Provides authentication responses to user service",
+							Technology:  "jwt",
+						},
+						{
+							Action:      servicefile.RelationshipActionReplies,
+							Participant: "notification",
+							Description: "Provides authentication status to notification service",
+							Technology:  "grpc",
+						},
+					},
+				},
+				{
+					Info: servicefile.Info{
+						Name:        "notification",
+						Description: "Notification service that handles sending notifications to users",
+					},
+					Relationships: []servicefile.Relationship{
+						{
+							Action:      servicefile.RelationshipActionRequests,
+							Participant: "auth",
+							Description: "Requests authentication status from auth service",
+							Technology:  "grpc",
+						},
+						{
+							Action:      servicefile.RelationshipActionReceives,
+							Participant: "user",
+							Description: "Receives user events from user service",
+							Technology:  "grpc",
+						},
+					},
+				},
+				{
+					Info: servicefile.Info{
+						Name:        "user",
+						Description: "User management service that handles user profiles and data",
+					},
+					Relationships: []servicefile.Relationship{
+						{
+							Action:      servicefile.RelationshipActionRequests,
+							Participant: "auth",
+							Description: "Requests authentication from auth service",
+							Technology:  "jwt",
+						},
+						{
+							Action:      servicefile.RelationshipActionSends,
+							Participant: "notification",
+							Description: "Sends user events to notification service",
+							Technology:  "grpc",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, lang := range languages {
+		for _, tt := range tests {
+			t.Run(lang.Name()+"/"+tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				serviceFiles, err := parser.Parse(filepath.Join("testdata", lang.Name(), tt.dir), true, false, parser.FromComments(lang))
+				require.NoError(t, err)
+
+				expected := make(map[string]*servicefile.ServiceFile, len(tt.expected))
+				for _, sf := range tt.expected {
+					sf := *sf
+					sf.Version = servicefile.Version
+					sf.Relationships = append([]servicefile.Relationship(nil), sf.Relationships...)
+					sf.Sort()
+					expected[sf.Info.Name] = &sf
+				}
+
+				actual := make(map[string]*servicefile.ServiceFile, len(serviceFiles))
+				for _, sf := range serviceFiles {
+					actual[sf.Info.Name] = sf
+				}
+
+				assert.Equal(t, expected, actual)
+			})
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		parser   *Parser
+		src      string
+		expected []language.Comment
+	}{
+		{
+			name:   "javadoc",
+			parser: NewJavaParser(),
+			src:    "/**\n * a: b\n *   c\n */\n@Component(\"x\")\npublic abstract class Handler {}\n",
+			expected: []language.Comment{
+				{Text: "/*\na: b\n  c\n */", Declaration: "Handler"},
+			},
+		},
+		{
+			name:   "java block comments don't nest",
+			parser: NewJavaParser(),
+			src:    "/* a /* b */ enum Kind {}\n",
+			expected: []language.Comment{
+				{Text: "/* a /* b */", Declaration: "Kind"},
+			},
+		},
+		{
+			name:   "kotlin block comments nest",
+			parser: NewKotlinParser(),
+			src:    "/* a /* b */ c */ suspend fun handle() {}\n",
+			expected: []language.Comment{
+				{Text: "/* a /* b */ c */", Declaration: "handle"},
+			},
+		},
+		{
+			name:   "comment markers in literals",
+			parser: NewJavaParser(),
+			src:    "String a = \"// x\", b = \"\"\"\n/* \\\"\"\" */\n\"\"\"; char c = '\\'';\n",
+		},
+		{
+			name:   "kotlin raw strings",
+			parser: NewKotlinParser(),
+			src:    "val a = \"\"\"\\\"\"\" // x\n",
+			expected: []language.Comment{
+				{Text: "// x"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.parser.syntax.Comments(tt.src))
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package com.example.api;
+
+import com.example.service.Service;
+
+/*
+service:replies
+description: Provides user management APIs to other services
+technology:grpc-server
+proto:grpc
+*/
+@Singleton
+public final class Server {
+    private final Service svc;
+
+    public Server(Service svc) {
+        this.svc = svc;
+    }
+}
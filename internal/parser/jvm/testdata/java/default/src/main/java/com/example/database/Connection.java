@@ -0,0 +1,13 @@
+package com.example.database;
+
+/**
+ * service:uses PostgreSQL
+ * description: Stores user data and authentication tokens
+ * technology:postgresql
+ * proto:tcp
+ */
+public class Connection {
+    private static final String QUERY = "SELECT 1 /* service:uses NotAnnotation */";
+
+    public void query() {}
+}
@@ -0,0 +1,15 @@
+package com.example.service;
+
+/**
+ * service:name Example
+ * description: Example service for exampling stuff.
+ */
+public class Service {
+    private static final String USAGE = """
+        // service:uses AlsoNotAnnotation
+        """;
+
+    private final char quote = '"';
+
+    public void run() {}
+}
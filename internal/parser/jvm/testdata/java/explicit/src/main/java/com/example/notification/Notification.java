@@ -0,0 +1,13 @@
+package com.example.notification;
+
+// service:name notification
+// description: Notification service that handles sending notifications to users
+
+// service:notification:requests auth
+// description: Requests authentication status from auth service
+// technology:grpc
+
+// service:notification:receives user
+// description: Receives user events from user service
+// technology:grpc
+public class Notification {}
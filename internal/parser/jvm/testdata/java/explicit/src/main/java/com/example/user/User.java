@@ -0,0 +1,13 @@
+package com.example.user;
+
+// service:name user
+// description: User management service that handles user profiles and data
+
+// service:user:requests auth
+// description: Requests authentication from auth service
+// technology:jwt
+
+// service:user:sends notification
+// description: Sends user events to notification service
+// technology:grpc
+public class User {}
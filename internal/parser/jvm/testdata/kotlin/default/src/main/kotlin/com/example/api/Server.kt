@@ -0,0 +1,14 @@
+package com.example.api
+
+import com.example.service.Service
+
+/* Kotlin block comments /* nest */ */
+
+/*
+service:replies
+description: Provides user management APIs to other services
+technology:grpc-server
+proto:grpc
+*/
+@Singleton
+class Server(private val svc: Service)
@@ -0,0 +1,9 @@
+package com.example.client
+
+// service:requests Firebase
+// description: Handles push notifications
+// technology:firebase
+// proto:http
+object Client {
+    suspend fun request() {}
+}
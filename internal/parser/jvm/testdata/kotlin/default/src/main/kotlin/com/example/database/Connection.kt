@@ -0,0 +1,11 @@
+package com.example.database
+
+/**
+ * service:uses PostgreSQL
+ * description: Stores user data and authentication tokens
+ * technology:postgresql
+ * proto:tcp
+ */
+class Connection {
+    fun query() = "SELECT 1 /* service:uses NotAnnotation */"
+}
@@ -0,0 +1,12 @@
+package com.example.service
+
+/**
+ * service:name Example
+ * description: Example service for exampling stuff.
+ */
+data class Service(val name: String) {
+    val usage = """C:\"""
+    val help = """
+        // service:uses AlsoNotAnnotation
+    """
+}
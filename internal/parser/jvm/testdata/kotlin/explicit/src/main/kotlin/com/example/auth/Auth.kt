@@ -0,0 +1,19 @@
+package com.example.auth
+
+/**
+ * service:name auth
+ * description: Authentication service that handles user authentication and authorization
+ */
+
+/**
+ * service:auth:replies user
+ * description: Provides authentication responses to user service
+ * technology:jwt
+ */
+
+/**
+ * service:auth:replies notification
+ * description: Provides authentication status to notification service
+ * technology:grpc
+ */
+object Auth
@@ -0,0 +1,333 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/holydocs/servicefile/internal/protobuf"
+	"github.com/holydocs/servicefile/pkg/language"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+// Language collects annotations from the source files of a programming language.
+type Language interface {
+	// Name returns the name the language is selected by, e.g. go.
+	Name() string
+	// Extensions returns the extensions of the source files of the language, e.g. .go.
+	Extensions() []string
+	// ParseFile collects the annotations found in a source file.
+	ParseFile(path string, src []byte, a *Annotations) error
+}
+
+// commentLanguage reads the annotations of the comments found by a language of the registry.
+type commentLanguage struct {
+	language.Language
+	extensions []string
+}
+
+// FromComments adapts a language finding comments to a Language. The extensions default
+// to the extensions of the language.
+func FromComments(lang language.Language, extensions ...string) Language {
+	if len(extensions) == 0 {
+		extensions = lang.Extensions()
+	}
+
+	return &commentLanguage{Language: lang, extensions: extensions}
+}
+
+func (l *commentLanguage) Extensions() []string {
+	return l.extensions
+}
+
+func (l *commentLanguage) ParseFile(path string, src []byte, a *Annotations) error {
+	comments, err := l.Comments(path, src)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range comments {
+		a.ParseComment(c.Text, Location{File: path, TypeName: c.Declaration})
+	}
+
+	return nil
+}
+
+// skippedDirs are directories holding third-party code, which are never walked.
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+}
+
+// protoSkippedDirs are directories holding third-party .proto files, which aren't parsed.
+var protoSkippedDirs = map[string]bool{
+	"vendor":      true,
+	"third_party": true,
+}
+
+// Config controls which files ParseFS reads and how strictly annotations are checked.
+type Config struct {
+	// Root is the directory of the file system to parse, "." when empty.
+	Root      string
+	Recursive bool
+	// Include limits parsing to the files matching any of the patterns, see path.Match.
+	// Patterns are matched against both the path relative to Root and the file name.
+	Include []string
+	// Exclude skips the files and directories matching any of the patterns.
+	Exclude []string
+	// Strict turns the issues of annotations, e.g. unknown fields, into errors.
+	Strict bool
+}
+
+// Parse walks dir, collects the annotations of the source files of the given languages,
+// detected by file extension, and builds the service files they describe.
+// The gRPC services declared in .proto files are available to all languages.
+func Parse(dir string, recursive bool, detectRepository bool, languages ...Language) ([]*servicefile.ServiceFile, error) {
+	return ParseDir(context.Background(), dir, Config{Recursive: recursive}, detectRepository, languages...)
+}
+
+// ParseDir is Parse configured by cfg.
+func ParseDir(ctx context.Context, dir string, cfg Config, detectRepository bool, languages ...Language) ([]*servicefile.ServiceFile, error) {
+	serviceFiles, err := ParseFS(ctx, os.DirFS(dir), cfg, languages...)
+	if err != nil {
+		return nil, err
+	}
+
+	if detectRepository && isEmptyRepository(serviceFiles) {
+		if err := fillRepository(dir, serviceFiles); err != nil {
+			return nil, fmt.Errorf("error detecting repositories: %w", err)
+		}
+	}
+
+	return serviceFiles, nil
+}
+
+// ParseFS is Parse for a file system. Paths of the parsed files are relative to the file system.
+func ParseFS(ctx context.Context, fsys fs.FS, cfg Config, languages ...Language) ([]*servicefile.ServiceFile, error) {
+	if cfg.Root == "" {
+		cfg.Root = "."
+	}
+
+	for _, pattern := range append(append([]string{}, cfg.Include...), cfg.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	byExtension := make(map[string]Language)
+	for _, lang := range languages {
+		for _, ext := range lang.Extensions() {
+			byExtension[ext] = lang
+		}
+	}
+
+	a := NewAnnotations()
+	a.FS = fsys
+
+	err := fs.WalkDir(fsys, cfg.Root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk the path: %w", err)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if p != cfg.Root && (!cfg.Recursive || skippedDirs[d.Name()] || cfg.matches(cfg.Exclude, p)) {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if cfg.matches(cfg.Exclude, p) || len(cfg.Include) > 0 && !cfg.matches(cfg.Include, p) {
+			return nil
+		}
+
+		if path.Ext(p) == ".proto" {
+			for _, dir := range strings.Split(path.Dir(p), "/") {
+				if protoSkippedDirs[dir] {
+					return nil
+				}
+			}
+
+			src, err := fs.ReadFile(fsys, p)
+			if err != nil {
+				return &SourceError{Path: p, Err: err}
+			}
+
+			// The .proto files the parser can't handle only matter if a service implements
+			// one of their gRPC services, see Annotations.ProtoErrors.
+			f, err := protobuf.Parse(p, string(src))
+			if err != nil {
+				a.ProtoErrors = append(a.ProtoErrors, &ProtoError{
+					SourceError: &SourceError{Path: p, Err: err},
+					Services:    protobuf.ServiceNames(string(src)),
+				})
+
+				return nil
+			}
+
+			a.ProtoServices = append(a.ProtoServices, f.Services...)
+
+			return nil
+		}
+
+		lang, ok := byExtension[path.Ext(p)]
+		if !ok {
+			return nil
+		}
+
+		src, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return &SourceError{Path: p, Err: err}
+		}
+
+		if err := lang.ParseFile(p, src, a); err != nil {
+			var annotationErr *AnnotationError
+			if errors.As(err, &annotationErr) {
+				return err
+			}
+
+			return &SourceError{Path: p, Err: err}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Strict && len(a.Issues) > 0 {
+		errs := make([]error, 0, len(a.Issues))
+		for _, issue := range a.Issues {
+			errs = append(errs, issue)
+		}
+
+		return nil, errors.Join(errs...)
+	}
+
+	return a.Build()
+}
+
+// matches reports whether the path relative to the root or the file name matches any of the patterns.
+func (cfg Config) matches(patterns []string, p string) bool {
+	rel := strings.TrimPrefix(strings.TrimPrefix(p, cfg.Root), "/")
+	if cfg.Root == "." {
+		rel = p
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+
+		if ok, _ := path.Match(pattern, path.Base(p)); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Walk calls fn for every file found in dir.
+func Walk(dir string, recursive bool, fn func(path string) error) error {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk the path: %w", err)
+		}
+
+		if info.IsDir() && path != dir && (!recursive || skippedDirs[info.Name()]) {
+			return filepath.SkipDir
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		return fn(path)
+	})
+	if err != nil {
+		return fmt.Errorf("error walking the path: %w", err)
+	}
+
+	return nil
+}
+
+func isEmptyRepository(serviceFiles []*servicefile.ServiceFile) bool {
+	for _, sf := range serviceFiles {
+		if sf.Info.Repository == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func fillRepository(dir string, serviceFiles []*servicefile.ServiceFile) error {
+	repoURL, err := detectGitRepository(dir)
+	if err != nil {
+		fmt.Printf("Couldn't detect git repository: %v\n", err.Error())
+		return nil
+	}
+
+	setRepository(serviceFiles, repoURL)
+
+	return nil
+}
+
+// FillRepository sets the repository of the service files that don't declare one to the
+// URL of the origin remote of the git repository at dir, if it can be detected.
+func FillRepository(dir string, serviceFiles []*servicefile.ServiceFile) {
+	if !isEmptyRepository(serviceFiles) {
+		return
+	}
+
+	if repoURL, err := detectGitRepository(dir); err == nil && repoURL != "" {
+		setRepository(serviceFiles, repoURL)
+	}
+}
+
+func setRepository(serviceFiles []*servicefile.ServiceFile, repoURL string) {
+	for _, sf := range serviceFiles {
+		if sf.Info.Repository == "" {
+			sf.Info.Repository = repoURL
+		}
+	}
+}
+
+func detectGitRepository(dir string) (string, error) {
+	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get git remote URL: %w", err)
+	}
+
+	url := strings.TrimSpace(string(output))
+	if url == "" {
+		return "", nil
+	}
+
+	return makeGitRepositoryURL(url), nil
+}
+
+func makeGitRepositoryURL(url string) string {
+	if strings.HasPrefix(url, "git@") {
+		url = strings.TrimPrefix(url, "git@")
+
+		parts := strings.SplitN(url, ":", 2)
+		if len(parts) == 2 {
+			url = "https://" + parts[0] + "/" + parts[1]
+		}
+	}
+
+	url = strings.TrimSuffix(url, ".git")
+
+	return url
+}
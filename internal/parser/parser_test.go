@@ -0,0 +1,48 @@
+package parser
+
+import "testing"
+
+func TestMakeGitRepositoryURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "git@github.com format",
+			input:    "git@github.com:holydocs/servicefile.git",
+			expected: "https://github.com/holydocs/servicefile",
+		},
+		{
+			name:     "https://github.com format with .git suffix",
+			input:    "https://github.com/holydocs/servicefile.git",
+			expected: "https://github.com/holydocs/servicefile",
+		},
+		{
+			name:     "http://github.com format with .git suffix",
+			input:    "http://github.com/holydocs/servicefile.git",
+			expected: "http://github.com/holydocs/servicefile",
+		},
+		{
+			name:     "complex nested path",
+			input:    "git@github.com:org/team/subteam/project.git",
+			expected: "https://github.com/org/team/subteam/project",
+		},
+		{
+			name:     "with multiple dots in path",
+			input:    "git@github.com:user/my-project.v2.git",
+			expected: "https://github.com/user/my-project.v2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := makeGitRepositoryURL(tt.input)
+			if result != tt.expected {
+				t.Errorf("makeGitRepositoryURL(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package python
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/holydocs/servicefile/pkg/language"
+)
+
+func init() {
+	language.MustRegister(NewParser())
+}
+
+// Parser collects annotations from the comments and docstrings of Python source code.
+type Parser struct{}
+
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Name returns the name of the language.
+func (p *Parser) Name() string {
+	return "python"
+}
+
+// Extensions returns the extensions of Python source files.
+func (p *Parser) Extensions() []string {
+	return []string{".py", ".pyi"}
+}
+
+// Comments returns the comments of a Python source file.
+func (p *Parser) Comments(_ string, src []byte) ([]language.Comment, error) {
+	return syntax.Comments(string(src)), nil
+}
+
+// syntax finds the # comments and docstrings of Python source code, skipping other string literals.
+var syntax = &language.Syntax{
+	LineComment: "#",
+	Literal: func(src string, i int) int {
+		if src[i] != '\'' && src[i] != '"' {
+			return i
+		}
+
+		end, _, _ := skipString(src, i)
+
+		return end
+	},
+	Docstring:   docstring,
+	Declaration: declarationPattern,
+}
+
+// docstring returns the docstring starting at i. A triple-quoted string is a docstring
+// when nothing but whitespace precedes it on its line.
+func docstring(src string, i int) (int, language.Comment, bool) {
+	if src[i] != '\'' && src[i] != '"' {
+		return i, language.Comment{}, false
+	}
+
+	start := stringStart(src, i)
+
+	end, body, triple := skipString(src, i)
+	if !triple || strings.TrimSpace(src[lineStart(src, start):start]) != "" {
+		return i, language.Comment{}, false
+	}
+
+	return end, language.Comment{
+		Text:        "/*\n" + cleanDocstring(body) + "\n*/",
+		Declaration: declarationBefore(src[:lineStart(src, start)]),
+	}, true
+}
+
+// stringStart returns the index of the prefix (r, b, f, u) of the string literal quoted at i.
+func stringStart(src string, i int) int {
+	start := i
+	for start > 0 && strings.ContainsRune("rRbBfFuU", rune(src[start-1])) {
+		start--
+	}
+
+	// The letters are part of an identifier rather than a prefix.
+	if start > 0 && isIdentByte(src[start-1]) || i-start > 2 {
+		return i
+	}
+
+	return start
+}
+
+// skipString returns the index following the string literal quoted at i, its body
+// and whether it is triple-quoted.
+func skipString(src string, i int) (int, string, bool) {
+	quote := src[i : i+1]
+	if strings.HasPrefix(src[i:], strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+
+	for j := i + len(quote); j < len(src); j++ {
+		switch {
+		case src[j] == '\\':
+			j++
+		case strings.HasPrefix(src[j:], quote):
+			return j + len(quote), src[i+len(quote) : j], len(quote) == 3
+		case src[j] == '\n' && len(quote) == 1:
+			return j, src[i+1 : j], false
+		}
+	}
+
+	return len(src), src[i+len(quote):], len(quote) == 3
+}
+
+func lineStart(src string, i int) int {
+	return strings.LastIndexByte(src[:i], '\n') + 1
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// cleanDocstring removes the indentation shared by the lines of a docstring following the first one,
+// the same way as inspect.cleandoc.
+func cleanDocstring(doc string) string {
+	lines := strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n")
+
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	lines[0] = strings.TrimSpace(lines[0])
+	for i := 1; i < len(lines); i++ {
+		switch {
+		case strings.TrimSpace(lines[i]) == "":
+			lines[i] = ""
+		case indent > 0:
+			lines[i] = lines[i][indent:]
+		}
+	}
+
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+var (
+	declarationPattern = regexp.MustCompile(`^\s*(?:@[^\n]*\n\s*)*(?:async\s+)?(?:class|def)\s+(\w+)`)
+	definitionPattern  = regexp.MustCompile(`^\s*(?:async\s+)?(?:class|def)\s+(\w+)[^\n]*:\s*$`)
+)
+
+// declarationBefore returns the name of the class or function defined by the last line of the source,
+// which a docstring following it documents.
+func declarationBefore(src string) string {
+	lines := strings.Split(strings.TrimRight(src, " \t\r\n"), "\n")
+
+	if match := definitionPattern.FindStringSubmatch(lines[len(lines)-1]); match != nil {
+		return match[1]
+	}
+
+	return ""
+}
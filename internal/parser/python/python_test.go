@@ -0,0 +1,131 @@
+package python
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/holydocs/servicefile/internal/parser"
+	"github.com/holydocs/servicefile/internal/parser/golang"
+	"github.com/holydocs/servicefile/internal/parser/typescript"
+	"github.com/holydocs/servicefile/pkg/language"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	serviceFiles, err := parser.Parse(filepath.Join("testdata", "service"), true, false, parser.FromComments(NewParser()))
+	require.NoError(t, err)
+	require.Len(t, serviceFiles, 1)
+
+	sf := serviceFiles[0]
+
+	assert.Equal(t, servicefile.Info{
+		Name:        "ReportService",
+		Description: "Builds analytics reports from the event warehouse",
+		Owner:       "team-data",
+		Tags:        []string{"analytics", "reports"},
+	}, sf.Info)
+
+	assert.Equal(t, []servicefile.Relationship{
+		{
+			Action:      servicefile.RelationshipActionReplies,
+			Participant: "Analyst",
+			Description: "Serves report downloads",
+			Technology:  "http-server",
+			Proto:       "http",
+			Person:      true,
+			Operations:  []string{"GET /reports/{id}"},
+		},
+		{
+			Action:      servicefile.RelationshipActionUses,
+			Participant: "ClickHouse",
+			Description: "Reads aggregated events",
+			Technology:  "clickhouse",
+			Proto:       "tcp",
+		},
+	}, sf.Relationships)
+}
+
+func TestParsePolyglot(t *testing.T) {
+	t.Parallel()
+
+	serviceFiles, err := parser.Parse(filepath.Join("testdata", "polyglot"), true, false,
+		golang.NewCommentParser(), parser.FromComments(typescript.NewParser()), parser.FromComments(NewParser()))
+	require.NoError(t, err)
+
+	sort.Slice(serviceFiles, func(i, j int) bool {
+		return serviceFiles[i].Info.Name < serviceFiles[j].Info.Name
+	})
+
+	names := make([]string, 0, len(serviceFiles))
+	for _, sf := range serviceFiles {
+		names = append(names, sf.Info.Name)
+	}
+
+	require.Equal(t, []string{"Gateway", "Web", "Worker"}, names)
+
+	assert.Equal(t, []servicefile.Relationship{
+		{Action: servicefile.RelationshipActionRequests, Participant: "Web", Proto: "http"},
+	}, serviceFiles[0].Relationships)
+
+	assert.Equal(t, []servicefile.Relationship{
+		{
+			Action:      servicefile.RelationshipActionSends,
+			Participant: "Kafka",
+			Description: "Schedules jobs",
+			Channel:     &servicefile.Channel{Name: "jobs"},
+		},
+	}, serviceFiles[1].Relationships)
+
+	assert.Equal(t, []servicefile.Relationship{
+		{
+			Action:      servicefile.RelationshipActionReceives,
+			Participant: "Kafka",
+			Description: "Consumes jobs",
+			Channel:     &servicefile.Channel{Name: "jobs"},
+		},
+	}, serviceFiles[2].Relationships)
+}
+
+func TestComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		src      string
+		expected []language.Comment
+	}{
+		{
+			name: "hash comments",
+			src:  "# a\n  # b\n\n# c\n@decorator\nclass C:\n    pass\n",
+			expected: []language.Comment{
+				{Text: "// a\n// b"},
+				{Text: "// c", Declaration: "C"},
+			},
+		},
+		{
+			name: "function docstring",
+			src:  "async def handle(request):\n    r'''a: b\n\n    c:\n      d\n    '''\n",
+			expected: []language.Comment{
+				{Text: "/*\na: b\n\nc:\n  d\n*/", Declaration: "handle"},
+			},
+		},
+		{
+			name: "comment markers in strings",
+			src:  "a = '# x'\nb = f\"\"\"# y\"\"\"\nc = \"\\\"# z\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, syntax.Comments(tt.src))
+		})
+	}
+}
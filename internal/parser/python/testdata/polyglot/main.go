@@ -0,0 +1,10 @@
+package main
+
+/*
+service:name Gateway
+description: Routes requests to the web application
+*/
+
+// service:Gateway:requests Web
+// proto: http
+type WebClient struct{}
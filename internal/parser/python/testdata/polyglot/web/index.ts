@@ -0,0 +1,9 @@
+/**
+ * service:name Web
+ * description: Customer facing web application
+ */
+
+// service:Web:sends Kafka
+// description: Schedules jobs
+// channel: jobs
+export function schedule() {}
@@ -0,0 +1,12 @@
+"""
+service:name Worker
+description: Processes background jobs
+"""
+
+
+async def consume():
+    """
+    service:Worker:receives Kafka
+    description: Consumes jobs
+    channel: jobs
+    """
@@ -0,0 +1,7 @@
+"""
+service:name ReportService
+description: Builds analytics reports
+  from the event warehouse
+owner: team-data
+tags: analytics, reports
+"""
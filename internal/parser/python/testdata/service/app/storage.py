@@ -0,0 +1,28 @@
+import psycopg
+
+QUERY = "SELECT 1 # service:uses NotAnnotation"
+TEMPLATE = '''
+service:uses AlsoNotAnnotation
+'''
+
+
+# service:uses ClickHouse
+# description: Reads aggregated events
+# technology: clickhouse
+# proto: tcp
+@dataclass
+class Warehouse:
+    dsn: str
+
+
+class Server:
+    """service:replies Analyst
+    description: Serves report downloads
+    technology: http-server
+    proto: http
+    person: true
+    operation: GET /reports/{id}
+    """
+
+    def serve(self):
+        pass
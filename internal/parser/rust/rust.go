@@ -0,0 +1,152 @@
+package rust
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/holydocs/servicefile/pkg/language"
+)
+
+func init() {
+	language.MustRegister(NewParser())
+}
+
+// Parser finds the comments of Rust source code.
+type Parser struct{}
+
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Name returns the name of the language.
+func (p *Parser) Name() string {
+	return "rust"
+}
+
+// Extensions returns the extensions of Rust source files.
+func (p *Parser) Extensions() []string {
+	return []string{".rs"}
+}
+
+// Comments returns the comments of a Rust source file.
+// Doc comments (///, //!, /** */ and /*! */) are returned with plain comment markers.
+func (p *Parser) Comments(_ string, src []byte) ([]language.Comment, error) {
+	return syntax.Comments(string(src)), nil
+}
+
+var syntax = &language.Syntax{
+	LineComment:  "//",
+	BlockStart:   "/*",
+	BlockEnd:     "*/",
+	NestedBlocks: true,
+	LineText: func(text string) string {
+		if strings.HasPrefix(text, "/") && !strings.HasPrefix(text, "//") || strings.HasPrefix(text, "!") {
+			return text[1:]
+		}
+
+		return text
+	},
+	Literal:     skipLiteral,
+	Declaration: declarationPattern,
+}
+
+// skipLiteral returns the index following the string or character literal starting at i.
+// Identifiers are skipped too, so that the r of e.g. bar"" isn't taken for a raw string.
+func skipLiteral(src string, i int) int {
+	switch {
+	case rawStringStart(src, i):
+		return skipRawString(src, i)
+	case src[i] == '"':
+		return skipString(src, i)
+	case src[i] == '\'':
+		return skipChar(src, i)
+	}
+
+	for i < len(src) && isIdentByte(src[i]) {
+		i++
+	}
+
+	return i
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// rawStringStart reports whether a raw string literal, e.g. r#"..."# or br"...", starts at i.
+func rawStringStart(src string, i int) bool {
+	if strings.HasPrefix(src[i:], "br") {
+		i++
+	}
+
+	if src[i] != 'r' {
+		return false
+	}
+
+	j := i + 1
+	for j < len(src) && src[j] == '#' {
+		j++
+	}
+
+	return j < len(src) && src[j] == '"'
+}
+
+// skipRawString returns the index following the raw string literal starting at i.
+func skipRawString(src string, i int) int {
+	if src[i] == 'b' {
+		i++
+	}
+
+	hashes := 0
+	for i++; src[i] == '#'; i++ {
+		hashes++
+	}
+
+	closing := `"` + strings.Repeat("#", hashes)
+
+	end := strings.Index(src[i+1:], closing)
+	if end < 0 {
+		return len(src)
+	}
+
+	return i + 1 + end + len(closing)
+}
+
+// skipString returns the index following the string literal starting at i.
+func skipString(src string, i int) int {
+	for i++; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+
+	return i
+}
+
+// skipChar returns the index following the character literal starting at i,
+// or the index following the quote of a lifetime, e.g. 'a.
+func skipChar(src string, i int) int {
+	if strings.HasPrefix(src[i:], `'\`) {
+		end := strings.IndexByte(src[i+3:], '\'')
+		if end < 0 {
+			return len(src)
+		}
+
+		return i + 3 + end + 1
+	}
+
+	_, size := utf8.DecodeRuneInString(src[i+1:])
+	if j := i + 1 + size; j < len(src) && src[j] == '\'' {
+		return j + 1
+	}
+
+	return i + 1
+}
+
+var declarationPattern = regexp.MustCompile(`^\s*(?:#!?\[[^\n]*\]\s*)*` +
+	`(?:(?:pub(?:\([^)]*\))?|async|const|unsafe|extern(?:\s+"[^"]*")?)\s+)*` +
+	`(?:struct|enum|trait|fn|mod|type|static|const|union)\s+(\w+)`)
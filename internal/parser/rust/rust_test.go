@@ -0,0 +1,83 @@
+package rust
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/holydocs/servicefile/internal/parser"
+	"github.com/holydocs/servicefile/pkg/language"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	serviceFiles, err := parser.Parse(filepath.Join("testdata", "service"), true, false, parser.FromComments(NewParser()))
+	require.NoError(t, err)
+	require.Len(t, serviceFiles, 1)
+
+	sf := serviceFiles[0]
+
+	assert.Equal(t, servicefile.Info{
+		Name:        "Ledger",
+		Description: "Keeps the books of all accounts",
+		Owner:       "team-finance",
+	}, sf.Info)
+
+	assert.Equal(t, []servicefile.Relationship{
+		{
+			Action:      servicefile.RelationshipActionReplies,
+			Description: "Serves ledger entries",
+			Technology:  "http-server",
+			Proto:       "http",
+			Operations:  []string{"GET /entries"},
+		},
+		{
+			Action:      servicefile.RelationshipActionUses,
+			Participant: "PostgreSQL",
+			Description: "Stores ledger entries",
+			Technology:  "postgresql",
+			Proto:       "tcp",
+		},
+	}, sf.Relationships)
+}
+
+func TestComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		src      string
+		expected []language.Comment
+	}{
+		{
+			name: "doc comments",
+			src:  "/// a\n///   b\n//// c\n#[derive(Debug)]\npub(crate) async fn handle() {}\n",
+			expected: []language.Comment{
+				{Text: "// a\n//   b\n//// c", Declaration: "handle"},
+			},
+		},
+		{
+			name: "block comments nest",
+			src:  "/*! a /* b */ c */ enum Kind {}\n",
+			expected: []language.Comment{
+				{Text: "/* a /* b */ c */", Declaration: "Kind"},
+			},
+		},
+		{
+			name: "comment markers in literals",
+			src:  "fn f<'a>(s: &'a str) { let a = \"// x\\\"\"; let b = br##\"/* \"# */\"##; let c = '/'; }\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, syntax.Comments(tt.src))
+		})
+	}
+}
@@ -0,0 +1,7 @@
+/**
+ * service:uses PostgreSQL
+ * description: Stores ledger entries
+ * technology: postgresql
+ * proto: tcp
+ */
+pub(crate) struct Pool;
@@ -0,0 +1,24 @@
+//! service:name Ledger
+//! description: Keeps the books
+//!   of all accounts
+//! owner: team-finance
+
+mod db;
+
+const BANNER: &str = r#"// service:uses NotAnnotation "quoted""#;
+
+/// service:replies
+/// description: Serves ledger entries
+/// technology: http-server
+/// proto: http
+/// operation: GET /entries
+#[derive(Clone)]
+pub struct Server<'a> {
+    name: &'a str,
+}
+
+fn main() {
+    let quote = '"';
+    let escaped = '\'';
+    let text = "/* service:uses AlsoNotAnnotation */";
+}
@@ -0,0 +1,10 @@
+const ratio = total / count; // not a regexp
+
+// service:requests UserService
+// description: Loads user profiles
+//   for the account page
+// technology: http
+// operation: GET /users/{id}
+export async function fetchUser(id) {
+  return fetch(`/users/${id}`);
+}
@@ -0,0 +1,26 @@
+/**
+ * service:name WebApp
+ * description: Customer facing web application
+ * owner: team-web
+ * tags: frontend, bff
+ */
+import { createServer } from "http";
+
+const banner = `/*
+service:uses NotAnnotation
+*/`;
+
+const pattern = /\/\* service:uses AlsoNotAnnotation \*\//g;
+
+/**
+ * service:replies Customer
+ * description: Serves the web interface
+ * technology: http-server
+ * proto: http
+ * person: true
+ */
+export class Server {
+  greeting = `Hello ${"world" + `${banner}`}`;
+}
+
+createServer();
@@ -0,0 +1,186 @@
+package typescript
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/holydocs/servicefile/pkg/language"
+)
+
+func init() {
+	language.MustRegister(NewParser())
+}
+
+// Parser collects annotations from the comments of TypeScript and JavaScript source code.
+type Parser struct{}
+
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Name returns the name of the language.
+func (p *Parser) Name() string {
+	return "typescript"
+}
+
+// Extensions returns the extensions of TypeScript and JavaScript source files.
+func (p *Parser) Extensions() []string {
+	return []string{".ts", ".tsx", ".mts", ".cts", ".js", ".jsx", ".mjs", ".cjs"}
+}
+
+// Comments returns the comments of a TypeScript or JavaScript source file.
+func (p *Parser) Comments(_ string, src []byte) ([]language.Comment, error) {
+	return newSyntax().Comments(string(src)), nil
+}
+
+// newSyntax returns the syntax of TypeScript and JavaScript comments. Its literals are skipped
+// by a lexer of its own, which keeps the state of a single source file.
+func newSyntax() *language.Syntax {
+	return &language.Syntax{
+		LineComment: "//",
+		BlockStart:  "/*",
+		BlockEnd:    "*/",
+		Literal:     new(lexer).skip,
+		Declaration: declarationPattern,
+	}
+}
+
+// regexpPrecedingKeywords are keywords after which a slash starts a regular expression.
+var regexpPrecedingKeywords = map[string]bool{
+	"return": true, "typeof": true, "instanceof": true, "in": true, "of": true, "new": true,
+	"delete": true, "void": true, "throw": true, "case": true, "do": true, "else": true,
+	"yield": true, "await": true,
+}
+
+// lexer skips string, template and regular expression literals. Telling a regular expression
+// from a division needs the last token, and finding the end of a ${ expression of a template
+// literal needs the brace depth.
+type lexer struct {
+	// prev is the last significant character or word outside of comments.
+	prev string
+	// templates holds the brace depth of each ${ expression of nested template literals.
+	templates []int
+	depth     int
+}
+
+// skip returns the index following the literal or token starting at i, or i at whitespace.
+func (l *lexer) skip(src string, i int) int {
+	c := src[i]
+
+	switch {
+	case c == '\'' || c == '"':
+		i = language.SkipString(src, i)
+		l.prev = "a"
+	case c == '`':
+		i = l.skipTemplate(src, i+1)
+		l.prev = "a"
+	case c == '/' && startsRegexp(l.prev):
+		i = skipRegexp(src, i)
+		l.prev = "a"
+	case c == '{':
+		l.depth++
+		l.prev = "{"
+		i++
+	case c == '}':
+		l.depth--
+		i++
+		l.prev = "}"
+
+		// Closing brace of a ${ expression resumes the template literal.
+		if n := len(l.templates); n > 0 && l.templates[n-1] == l.depth {
+			l.templates = l.templates[:n-1]
+
+			i = l.skipTemplate(src, i)
+			l.prev = "a"
+		}
+	case isIdentByte(c):
+		start := i
+		for i < len(src) && isIdentByte(src[i]) {
+			i++
+		}
+		l.prev = src[start:i]
+	case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+	default:
+		l.prev = string(c)
+		i++
+	}
+
+	return i
+}
+
+// skipTemplate returns the index following the template literal continuing at i,
+// or the index following a ${ starting an expression of it.
+func (l *lexer) skipTemplate(src string, i int) int {
+	i = skipTemplate(src, i)
+	if i < len(src) && strings.HasPrefix(src[i:], "${") {
+		l.templates = append(l.templates, l.depth)
+		l.depth++
+		i += 2
+	}
+
+	return i
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// startsRegexp reports whether a slash following prev starts a regular expression rather than a division.
+func startsRegexp(prev string) bool {
+	if prev == "" {
+		return true
+	}
+
+	if isIdentByte(prev[0]) {
+		return regexpPrecedingKeywords[prev]
+	}
+
+	return prev != ")" && prev != "]" && prev != "}"
+}
+
+// skipTemplate returns the index of the closing backtick plus one,
+// or the index of a ${ starting an expression of the template literal.
+func skipTemplate(src string, i int) int {
+	for ; i < len(src); i++ {
+		switch {
+		case src[i] == '\\':
+			i++
+		case src[i] == '`':
+			return i + 1
+		case strings.HasPrefix(src[i:], "${"):
+			return i
+		}
+	}
+
+	return i
+}
+
+// skipRegexp returns the index following the regular expression literal starting at i.
+func skipRegexp(src string, i int) int {
+	inClass := false
+
+	for i++; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '\\':
+			i++
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '\n':
+			return i
+		case c == '/' && !inClass:
+			i++
+			for i < len(src) && isIdentByte(src[i]) {
+				i++
+			}
+			return i
+		}
+	}
+
+	return i
+}
+
+var declarationPattern = regexp.MustCompile(`^\s*(?:@[\w.]+(?:\([^)]*\))?\s*)*` +
+	`(?:(?:export|default|declare|abstract|async)\s+)*` +
+	`(?:class|interface|function\*?|const|let|var|type|enum|namespace)\s+([A-Za-z_$][\w$]*)`)
@@ -0,0 +1,89 @@
+package typescript
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/holydocs/servicefile/internal/parser"
+	"github.com/holydocs/servicefile/pkg/language"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	serviceFiles, err := parser.Parse(filepath.Join("testdata", "app"), true, false, parser.FromComments(NewParser()))
+	require.NoError(t, err)
+	require.Len(t, serviceFiles, 1)
+
+	sf := serviceFiles[0]
+
+	assert.Equal(t, servicefile.Info{
+		Name:        "WebApp",
+		Description: "Customer facing web application",
+		Owner:       "team-web",
+		Tags:        []string{"frontend", "bff"},
+	}, sf.Info)
+
+	assert.Equal(t, []servicefile.Relationship{
+		{
+			Action:      servicefile.RelationshipActionReplies,
+			Participant: "Customer",
+			Description: "Serves the web interface",
+			Technology:  "http-server",
+			Proto:       "http",
+			Person:      true,
+		},
+		{
+			Action:      servicefile.RelationshipActionRequests,
+			Participant: "UserService",
+			Description: "Loads user profiles for the account page",
+			Technology:  "http",
+			Operations:  []string{"GET /users/{id}"},
+		},
+	}, sf.Relationships)
+}
+
+func TestComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		src      string
+		expected []language.Comment
+	}{
+		{
+			name: "jsdoc block",
+			src:  "/**\n * a: b\n *   c\n */\n@Injectable()\nexport default class Service {}\n",
+			expected: []language.Comment{
+				{Text: "/*\na: b\n  c\n */", Declaration: "Service"},
+			},
+		},
+		{
+			name: "comment markers in literals",
+			src:  "const a = '// x', b = \"/* y */\", c = `${a} // z ${`/*`}`, d = /\\/\\/[/*]/i;\n",
+		},
+		{
+			name: "division is not a regexp",
+			src:  "const a = (b) / 2 // x\n",
+			expected: []language.Comment{
+				{Text: "// x"},
+			},
+		},
+		{
+			name: "regexp after keyword",
+			src:  "function f() { return /\\/\\/ x/.test(s) }\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, newSyntax().Comments(tt.src))
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package protobuf
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// File represents the parts of a .proto file describing gRPC services.
+type File struct {
+	Path      string
+	Package   string
+	GoPackage string
+	Services  []Service
+}
+
+// Service represents a gRPC service definition.
+type Service struct {
+	Name    string
+	Package string
+	Methods []Method
+}
+
+// Method represents an RPC of a service.
+type Method struct {
+	Name            string
+	InputType       string
+	OutputType      string
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// FullName returns the name of the service qualified with its package, e.g. user.v1.UserService.
+func (s Service) FullName() string {
+	if s.Package == "" {
+		return s.Name
+	}
+
+	return s.Package + "." + s.Name
+}
+
+// Operations returns the RPCs of the service as operations, e.g. user.v1.UserService/GetUser.
+func (s Service) Operations() []string {
+	operations := make([]string, 0, len(s.Methods))
+	for _, m := range s.Methods {
+		operations = append(operations, s.FullName()+"/"+m.Name)
+	}
+
+	return operations
+}
+
+// ParseFile reads and parses a .proto file at the given path.
+func ParseFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	return Parse(path, string(data))
+}
+
+// Parse parses the source of a .proto file. It understands enough of the protobuf language
+// to find the package, the go_package option and the services with their RPCs; messages,
+// enums and options are skipped.
+func Parse(path, src string) (*File, error) {
+	p := &parser{path: path, tokens: tokenize(src)}
+
+	f, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	f.Path = path
+
+	return f, nil
+}
+
+// ServiceNames returns the names of the services declared in the source of a .proto file,
+// as far as they can be found in a file Parse fails on.
+func ServiceNames(src string) []string {
+	var names []string
+
+	tokens := tokenize(src)
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].text != "service" || tokens[i].str || tokens[i+1].str || !isIdentRune([]rune(tokens[i+1].text)[0]) {
+			continue
+		}
+
+		names = append(names, tokens[i+1].text)
+	}
+
+	return names
+}
+
+type token struct {
+	text string
+	line int
+	// str is set for string literals, text holds the unquoted value.
+	str bool
+}
+
+// tokenize splits the source into identifiers, string literals and symbols, dropping comments.
+func tokenize(src string) []token {
+	var (
+		tokens []token
+		line   = 1
+		runes  = []rune(src)
+	)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i < len(runes) && (runes[i] != '*' || i+1 >= len(runes) || runes[i+1] != '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			i += 2
+		case r == '"' || r == '\'':
+			start := line
+			var b strings.Builder
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				if runes[i] == '\n' {
+					line++
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			i++
+			tokens = append(tokens, token{text: b.String(), line: start, str: true})
+		case isIdentRune(r):
+			start := i
+			for i < len(runes) && isIdentRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{text: string(runes[start:i]), line: line})
+		default:
+			tokens = append(tokens, token{text: string(r), line: line})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type parser struct {
+	path   string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) parse() (*File, error) {
+	f := &File{}
+
+	for !p.done() {
+		t := p.next()
+
+		switch {
+		case t.str:
+			return nil, p.errorf(t, "unexpected string %q", t.text)
+		case t.text == "package":
+			name, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+
+			f.Package = name
+
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case t.text == "option":
+			name, value, err := p.option()
+			if err != nil {
+				return nil, err
+			}
+
+			if name == "go_package" {
+				f.GoPackage = value
+			}
+		case t.text == "service":
+			s, err := p.service()
+			if err != nil {
+				return nil, err
+			}
+
+			f.Services = append(f.Services, s)
+		case t.text == "{":
+			if err := p.skipBlock(t); err != nil {
+				return nil, err
+			}
+		case t.text == ";":
+		default:
+			// syntax, edition, import, message, enum and extend statements.
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// The package statement may follow the services.
+	for i := range f.Services {
+		f.Services[i].Package = f.Package
+	}
+
+	return f, nil
+}
+
+func (p *parser) service() (Service, error) {
+	name, err := p.ident()
+	if err != nil {
+		return Service{}, err
+	}
+
+	s := Service{Name: name}
+
+	if err := p.expect("{"); err != nil {
+		return Service{}, err
+	}
+
+	for {
+		if p.done() {
+			return Service{}, fmt.Errorf("%s: unterminated service %s", p.path, name)
+		}
+
+		t := p.next()
+
+		switch t.text {
+		case "}":
+			return s, nil
+		case ";":
+		case "rpc":
+			m, err := p.method()
+			if err != nil {
+				return Service{}, err
+			}
+
+			s.Methods = append(s.Methods, m)
+		case "option":
+			if _, _, err := p.option(); err != nil {
+				return Service{}, err
+			}
+		default:
+			return Service{}, p.errorf(t, "unexpected %q in service %s", t.text, name)
+		}
+	}
+}
+
+// method parses "Name (stream Input) returns (stream Output)" followed by ";" or an options block.
+func (p *parser) method() (Method, error) {
+	name, err := p.ident()
+	if err != nil {
+		return Method{}, err
+	}
+
+	m := Method{Name: name}
+
+	if m.InputType, m.ClientStreaming, err = p.methodType(); err != nil {
+		return Method{}, err
+	}
+
+	if err := p.expect("returns"); err != nil {
+		return Method{}, err
+	}
+
+	if m.OutputType, m.ServerStreaming, err = p.methodType(); err != nil {
+		return Method{}, err
+	}
+
+	if p.done() {
+		return Method{}, fmt.Errorf("%s: unterminated rpc %s", p.path, name)
+	}
+
+	switch t := p.next(); t.text {
+	case ";":
+	case "{":
+		if err := p.skipBlock(t); err != nil {
+			return Method{}, err
+		}
+	default:
+		return Method{}, p.errorf(t, "expected ; or { after rpc %s, got %q", name, t.text)
+	}
+
+	return m, nil
+}
+
+func (p *parser) methodType() (string, bool, error) {
+	if err := p.expect("("); err != nil {
+		return "", false, err
+	}
+
+	typ, err := p.ident()
+	if err != nil {
+		return "", false, err
+	}
+
+	stream := false
+	if typ == "stream" && !p.done() && p.peek().text != ")" {
+		stream = true
+
+		if typ, err = p.ident(); err != nil {
+			return "", false, err
+		}
+	}
+
+	if err := p.expect(")"); err != nil {
+		return "", false, err
+	}
+
+	return typ, stream, nil
+}
+
+// option parses "name = value;" and "(custom.name) = value;", returning string values only.
+func (p *parser) option() (string, string, error) {
+	var name strings.Builder
+
+	for !p.done() && p.peek().text != "=" {
+		name.WriteString(p.next().text)
+	}
+
+	if err := p.expect("="); err != nil {
+		return "", "", err
+	}
+
+	var value string
+	if !p.done() && p.peek().str {
+		value = p.next().text
+	}
+
+	if err := p.skipStatement(); err != nil {
+		return "", "", err
+	}
+
+	return name.String(), value, nil
+}
+
+// skipStatement skips tokens up to the end of the current statement,
+// which is either a ";" or a block.
+func (p *parser) skipStatement() error {
+	for !p.done() {
+		t := p.next()
+
+		switch t.text {
+		case ";":
+			return nil
+		case "{":
+			return p.skipBlock(t)
+		}
+	}
+
+	return nil
+}
+
+// skipBlock skips tokens up to the brace closing the already consumed open brace.
+func (p *parser) skipBlock(open token) error {
+	depth := 1
+
+	for !p.done() {
+		t := p.next()
+		if t.str {
+			continue
+		}
+
+		switch t.text {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+
+	return p.errorf(open, "unterminated block")
+}
+
+func (p *parser) ident() (string, error) {
+	if p.done() {
+		return "", fmt.Errorf("%s: unexpected end of file, expected identifier", p.path)
+	}
+
+	t := p.next()
+	if t.str || !isIdentRune([]rune(t.text)[0]) {
+		return "", p.errorf(t, "expected identifier, got %q", t.text)
+	}
+
+	return t.text, nil
+}
+
+func (p *parser) expect(text string) error {
+	if p.done() {
+		return fmt.Errorf("%s: unexpected end of file, expected %q", p.path, text)
+	}
+
+	if t := p.next(); t.str || t.text != text {
+		return p.errorf(t, "expected %q, got %q", text, t.text)
+	}
+
+	return nil
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	p.pos++
+
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", p.path, t.line, fmt.Sprintf(format, args...))
+}
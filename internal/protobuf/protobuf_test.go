@@ -0,0 +1,127 @@
+package protobuf
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	src := `
+// Orders API.
+syntax = "proto3";
+
+import "google/api/annotations.proto";
+
+option go_package = "example.com/orders/gen/order/v1;orderv1";
+option java_multiple_files = true;
+
+message Order {
+  string id = 1;
+  map<string, string> labels = 2;
+  oneof kind {
+    string note = 3;
+  }
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+}
+
+/* OrderService manages orders. { } */
+service OrderService {
+  option deprecated = false;
+
+  rpc GetOrder(GetOrderRequest) returns (Order) {
+    option (google.api.http) = { get: "/v1/orders/{id}" };
+  };
+  rpc WatchOrders (stream .order.v1.WatchRequest) returns (stream Order);
+  rpc Stream(stream) returns (Order);
+}
+
+service Empty {}
+
+package order.v1;
+`
+
+	f, err := Parse("order.proto", src)
+	require.NoError(t, err)
+
+	assert.Equal(t, "order.v1", f.Package)
+	assert.Equal(t, "example.com/orders/gen/order/v1;orderv1", f.GoPackage)
+	require.Len(t, f.Services, 2)
+
+	assert.Equal(t, Service{
+		Name:    "OrderService",
+		Package: "order.v1",
+		Methods: []Method{
+			{Name: "GetOrder", InputType: "GetOrderRequest", OutputType: "Order"},
+			{Name: "WatchOrders", InputType: ".order.v1.WatchRequest", OutputType: "Order", ClientStreaming: true, ServerStreaming: true},
+			{Name: "Stream", InputType: "stream", OutputType: "Order"},
+		},
+	}, f.Services[0])
+	assert.Equal(t, []string{
+		"order.v1.OrderService/GetOrder",
+		"order.v1.OrderService/WatchOrders",
+		"order.v1.OrderService/Stream",
+	}, f.Services[0].Operations())
+
+	assert.Equal(t, "order.v1.Empty", f.Services[1].FullName())
+	assert.Empty(t, f.Services[1].Operations())
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "unterminated service", src: "service A {\n rpc B(C) returns (D);"},
+		{name: "missing returns", src: "service A {\n rpc B(C) (D);\n}"},
+		{name: "unexpected statement in service", src: "service A {\n message B {}\n}"},
+		{name: "unterminated message", src: "message A {\n string b = 1;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse("test.proto", tt.src)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestServiceNames(t *testing.T) {
+	t.Parallel()
+
+	src := `// service Commented {}
+message Order {
+  string service = 1;
+}
+
+service OrderService {
+  rpc GetOrder(GetOrderRequest) returns (Order) {
+    option (custom) = { unsupported: [1, 2] };
+  }
+}
+
+service AdminService {`
+
+	_, err := Parse("order.proto", src)
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"OrderService", "AdminService"}, ServiceNames(src))
+}
+
+func TestParseFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseFile(filepath.Join("testdata", "missing.proto"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,293 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+// AddressExtension is the extension field recording the address of the resource a
+// relationship was merged from, so merging again completes the same relationship.
+const AddressExtension = "x-terraform-address"
+
+// DefaultTagKeys are the AWS tags and Google Cloud labels the service owning a resource
+// is read from, in order.
+var DefaultTagKeys = []string{"service", "Service", "app", "application"}
+
+// kind describes how a resource type maps to a relationship.
+type kind struct {
+	action servicefile.RelationshipAction
+	// participant is the participant of sends and receives relationships, uses relationships
+	// use the name of the resource.
+	participant string
+	technology  string
+	// name is the attribute the name of the resource is read from.
+	name string
+	// engine is the attribute the technology is guessed from, if it depends on the resource.
+	engine string
+	// topic is the attribute the channel of subscriptions is read from.
+	topic string
+}
+
+// kinds maps the supported resource types to relationships. Queues and subscriptions are
+// read by the service owning them, topics and streams are written to.
+var kinds = map[string]kind{
+	"aws_db_instance":                   {action: servicefile.RelationshipActionUses, name: "identifier", engine: "engine"},
+	"aws_rds_cluster":                   {action: servicefile.RelationshipActionUses, name: "cluster_identifier", engine: "engine"},
+	"aws_dynamodb_table":                {action: servicefile.RelationshipActionUses, technology: "dynamodb", name: "name"},
+	"aws_elasticache_cluster":           {action: servicefile.RelationshipActionUses, name: "cluster_id", engine: "engine"},
+	"aws_elasticache_replication_group": {action: servicefile.RelationshipActionUses, technology: "redis", name: "replication_group_id"},
+	"aws_s3_bucket":                     {action: servicefile.RelationshipActionUses, technology: "s3", name: "bucket"},
+	"aws_msk_cluster":                   {action: servicefile.RelationshipActionUses, technology: "kafka", name: "cluster_name"},
+	"aws_sqs_queue":                     {action: servicefile.RelationshipActionReceives, participant: "SQS", technology: "sqs", name: "name"},
+	"aws_sns_topic":                     {action: servicefile.RelationshipActionSends, participant: "SNS", technology: "sns", name: "name"},
+	"aws_kinesis_stream":                {action: servicefile.RelationshipActionSends, participant: "Kinesis", technology: "kinesis", name: "name"},
+	"google_sql_database_instance":      {action: servicefile.RelationshipActionUses, name: "name", engine: "database_version"},
+	"google_redis_instance":             {action: servicefile.RelationshipActionUses, technology: "redis", name: "name"},
+	"google_storage_bucket":             {action: servicefile.RelationshipActionUses, technology: "gcs", name: "name"},
+	"google_bigquery_dataset":           {action: servicefile.RelationshipActionUses, technology: "bigquery", name: "dataset_id"},
+	"google_spanner_instance":           {action: servicefile.RelationshipActionUses, technology: "spanner", name: "name"},
+	"google_pubsub_topic":               {action: servicefile.RelationshipActionSends, participant: "Pub/Sub", technology: "pubsub", name: "name"},
+	"google_pubsub_subscription": {
+		action: servicefile.RelationshipActionReceives, participant: "Pub/Sub", technology: "pubsub", name: "name", topic: "topic",
+	},
+}
+
+// engineTechnologies maps database engines to technologies. An engine matches the first
+// entry it contains, e.g. aurora-postgresql or POSTGRES_15.
+var engineTechnologies = []struct {
+	name       string
+	technology string
+}{
+	{"postgres", "postgresql"},
+	{"mysql", "mysql"},
+	{"aurora", "mysql"},
+	{"mariadb", "mariadb"},
+	{"oracle", "oracle"},
+	{"sqlserver", "sqlserver"},
+	{"redis", "redis"},
+	{"valkey", "redis"},
+	{"memcached", "memcached"},
+}
+
+// Relationship converts a resource into an external relationship. It returns false for
+// unsupported resource types.
+func Relationship(r Resource) (servicefile.Relationship, bool) {
+	k, ok := kinds[r.Type]
+	if !ok {
+		return servicefile.Relationship{}, false
+	}
+
+	rel := servicefile.Relationship{
+		Action:      k.action,
+		Participant: r.resourceName(k),
+		Technology:  k.technology,
+		External:    true,
+	}
+
+	if k.engine != "" {
+		engine := strings.ToLower(r.value(k.engine))
+		for _, et := range engineTechnologies {
+			if strings.Contains(engine, et.name) {
+				rel.Technology = et.technology
+				break
+			}
+		}
+	}
+
+	if k.participant != "" {
+		channel := rel.Participant
+		if k.topic != "" {
+			if topic := r.value(k.topic); topic != "" {
+				channel = topic[strings.LastIndexByte(topic, '/')+1:]
+			}
+		}
+
+		rel.Participant = k.participant
+		rel.Channel = &servicefile.Channel{Name: channel}
+	}
+
+	return rel, true
+}
+
+// Result describes where a resource was merged to.
+type Result struct {
+	Resource     Resource
+	Relationship servicefile.Relationship
+	// Service is the name of the service the resource belongs to, empty if it belongs to none.
+	Service string
+	// Match explains why the resource belongs to the service, e.g. tag service=orders.
+	Match string
+	// Merged is set if the resource completed a relationship of the service file instead
+	// of being added to it.
+	Merged bool
+}
+
+func (r Result) String() string {
+	target := r.Relationship.Participant
+	if r.Relationship.Channel != nil {
+		target += " channel " + r.Relationship.Channel.Name
+	}
+
+	if r.Service == "" {
+		return fmt.Sprintf("%s: no service matched for %s %s", r.Resource.Address, r.Relationship.Action, target)
+	}
+
+	verb := "added"
+	if r.Merged {
+		verb = "merged into"
+	}
+
+	return fmt.Sprintf("%s: %s %s %s from %s, matched by %s",
+		r.Service, verb, r.Relationship.Action, target, r.Resource.Address, r.Match)
+}
+
+// Merge adds the supported resources to the service files they belong to. A resource belongs
+// to the service named by the first of its tags or labels in tagKeys, or else to the service
+// with the longest name its own name contains, e.g. orders-db and prod-orders-events belong
+// to orders. The relationship of the service file merged from the same resource before, or
+// else to the same participant or channel, or else a uses relationship of the same technology
+// no other resource was merged into, is completed and marked external instead of being
+// duplicated, so its action is kept. The address of the resource is recorded in the
+// AddressExtension field of the relationship, so merging again changes nothing.
+func Merge(serviceFiles []*servicefile.ServiceFile, resources []Resource, tagKeys []string) []Result {
+	var results []Result
+
+	for _, r := range resources {
+		rel, ok := Relationship(r)
+		if !ok {
+			continue
+		}
+
+		result := Result{Resource: r, Relationship: rel}
+
+		sf, match := owner(r, serviceFiles, tagKeys)
+		if sf != nil {
+			result.Service = sf.Info.Name
+			result.Match = match
+			result.Merged = merge(sf, rel, r.Address)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// owner returns the service file the resource belongs to and why.
+func owner(r Resource, serviceFiles []*servicefile.ServiceFile, tagKeys []string) (*servicefile.ServiceFile, string) {
+	tags := r.tags()
+
+	for _, key := range tagKeys {
+		value, ok := tags[key]
+		if !ok {
+			continue
+		}
+
+		for _, sf := range serviceFiles {
+			if normalize(sf.Info.Name) == normalize(value) {
+				return sf, fmt.Sprintf("tag %s=%s", key, value)
+			}
+		}
+	}
+
+	name := r.resourceName(kinds[r.Type])
+	words := "-" + normalize(name) + "-"
+
+	var best *servicefile.ServiceFile
+
+	for _, sf := range serviceFiles {
+		service := normalize(sf.Info.Name)
+		if service == "" || !strings.Contains(words, "-"+service+"-") {
+			continue
+		}
+
+		if best == nil || len(service) > len(normalize(best.Info.Name)) {
+			best = sf
+		}
+	}
+
+	if best == nil {
+		return nil, ""
+	}
+
+	return best, fmt.Sprintf("name %s", name)
+}
+
+// merge completes the relationship of the service file matching rel of the resource at the
+// address, or adds rel. It returns true if an existing relationship was completed.
+func merge(sf *servicefile.ServiceFile, rel servicefile.Relationship, address string) bool {
+	matchers := []func(existing servicefile.Relationship) bool{
+		func(existing servicefile.Relationship) bool {
+			return existing.Extensions[AddressExtension] == address
+		},
+		func(existing servicefile.Relationship) bool {
+			if rel.Channel != nil {
+				return existing.Channel != nil && existing.Channel.Name == rel.Channel.Name &&
+					(existing.Action == servicefile.RelationshipActionSends ||
+						existing.Action == servicefile.RelationshipActionReceives)
+			}
+
+			return existing.Action == rel.Action && strings.EqualFold(existing.Participant, rel.Participant)
+		},
+		func(existing servicefile.Relationship) bool {
+			return rel.Channel == nil && rel.Technology != "" &&
+				existing.Action == rel.Action && existing.Technology == rel.Technology &&
+				existing.Extensions[AddressExtension] == nil
+		},
+	}
+
+	for _, matches := range matchers {
+		for i := range sf.Relationships {
+			existing := &sf.Relationships[i]
+			if !matches(*existing) {
+				continue
+			}
+
+			if existing.Technology == "" {
+				existing.Technology = rel.Technology
+			}
+
+			existing.External = true
+
+			if existing.Extensions[AddressExtension] == nil {
+				if existing.Extensions == nil {
+					existing.Extensions = make(servicefile.Extensions)
+				}
+
+				existing.Extensions[AddressExtension] = address
+			}
+
+			return true
+		}
+	}
+
+	rel.Extensions = servicefile.Extensions{AddressExtension: address}
+	sf.Relationships = append(sf.Relationships, rel)
+	sf.Sort()
+
+	return false
+}
+
+// resourceName returns the name of the cloud resource, or the name of the Terraform
+// resource if it's unknown, e.g. until it's created.
+func (r Resource) resourceName(k kind) string {
+	if name := r.value(k.name); name != "" {
+		return name
+	}
+
+	return r.Name
+}
+
+// normalize lowercases a name and replaces its separators with dashes.
+func normalize(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '.', ' ':
+			return '-'
+		}
+
+		return r
+	}, strings.ToLower(name))
+}
@@ -0,0 +1,122 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// State represents the output of terraform show -json for a state or a saved plan.
+// Only the resource values are modelled.
+type State struct {
+	FormatVersion string `json:"format_version"`
+	// Values are set for states.
+	Values *Values `json:"values,omitempty"`
+	// PlannedValues are set for plans.
+	PlannedValues *Values `json:"planned_values,omitempty"`
+}
+
+// Values represents the resources of a state or plan.
+type Values struct {
+	RootModule Module `json:"root_module"`
+}
+
+// Module represents a module and the resources declared in it.
+type Module struct {
+	Address      string     `json:"address,omitempty"`
+	Resources    []Resource `json:"resources,omitempty"`
+	ChildModules []Module   `json:"child_modules,omitempty"`
+}
+
+// Resource represents a resource, e.g. aws_db_instance.orders.
+type Resource struct {
+	Address      string         `json:"address"`
+	Mode         string         `json:"mode"`
+	Type         string         `json:"type"`
+	Name         string         `json:"name"`
+	ProviderName string         `json:"provider_name,omitempty"`
+	Values       map[string]any `json:"values,omitempty"`
+}
+
+// Load reads and parses the output of terraform show -json from a file at the given path.
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	return Parse(path, data)
+}
+
+// Parse parses the output of terraform show -json, path is used in error messages.
+func Parse(path string, data []byte) (*State, error) {
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", path, err)
+	}
+
+	if s.FormatVersion == "" {
+		return nil, fmt.Errorf("unsupported file %s: expected the output of terraform show -json", path)
+	}
+
+	return &s, nil
+}
+
+// Resources returns the managed resources of all modules, sorted by address.
+// The planned values are preferred to the values of the state.
+func (s *State) Resources() []Resource {
+	values := s.PlannedValues
+	if values == nil {
+		values = s.Values
+	}
+
+	if values == nil {
+		return nil
+	}
+
+	var resources []Resource
+
+	var collect func(m Module)
+	collect = func(m Module) {
+		for _, r := range m.Resources {
+			if r.Mode == "managed" {
+				resources = append(resources, r)
+			}
+		}
+
+		for _, child := range m.ChildModules {
+			collect(child)
+		}
+	}
+
+	collect(values.RootModule)
+
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Address < resources[j].Address
+	})
+
+	return resources
+}
+
+// value returns the string value of an attribute of the resource.
+func (r Resource) value(key string) string {
+	s, _ := r.Values[key].(string)
+	return s
+}
+
+// tags returns the AWS tags or the Google Cloud labels of the resource.
+func (r Resource) tags() map[string]string {
+	tags := make(map[string]string)
+
+	for _, key := range []string{"labels", "tags"} {
+		values, _ := r.Values[key].(map[string]any)
+		for k, v := range values {
+			if s, ok := v.(string); ok {
+				tags[k] = s
+			}
+		}
+	}
+
+	return tags
+}
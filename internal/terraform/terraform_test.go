@@ -0,0 +1,228 @@
+package terraform
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+func addresses(resources []Resource) []string {
+	result := make([]string, 0, len(resources))
+	for _, r := range resources {
+		result = append(result, r.Address)
+	}
+
+	return result
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	state, err := Load(filepath.Join("testdata", "state.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"aws_db_instance.orders",
+		"aws_iam_role.orders",
+		"aws_s3_bucket.invoices",
+		"aws_s3_bucket.logs",
+		"aws_sqs_queue.order_events",
+	}, addresses(state.Resources()))
+
+	plan, err := Load(filepath.Join("testdata", "plan.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"google_pubsub_topic.orders",
+		"module.shipping.google_pubsub_subscription.orders",
+		"module.shipping.google_sql_database_instance.main",
+	}, addresses(plan.Resources()))
+
+	_, err = Parse("servicefile.json", []byte(`{"servicefile": "0.1.0"}`))
+	assert.ErrorContains(t, err, "terraform show -json")
+}
+
+func TestRelationship(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		resource Resource
+		expected servicefile.Relationship
+	}{
+		{
+			name: "database engine",
+			resource: Resource{Type: "aws_rds_cluster", Name: "main", Values: map[string]any{
+				"cluster_identifier": "orders", "engine": "aurora-postgresql",
+			}},
+			expected: servicefile.Relationship{
+				Action: servicefile.RelationshipActionUses, Participant: "orders", Technology: "postgresql", External: true,
+			},
+		},
+		{
+			name:     "name not known until applied",
+			resource: Resource{Type: "google_sql_database_instance", Name: "main", Values: map[string]any{"database_version": "MYSQL_8_0"}},
+			expected: servicefile.Relationship{
+				Action: servicefile.RelationshipActionUses, Participant: "main", Technology: "mysql", External: true,
+			},
+		},
+		{
+			name:     "topic",
+			resource: Resource{Type: "aws_sns_topic", Name: "events", Values: map[string]any{"name": "order-created"}},
+			expected: servicefile.Relationship{
+				Action: servicefile.RelationshipActionSends, Participant: "SNS", Technology: "sns", External: true,
+				Channel: &servicefile.Channel{Name: "order-created"},
+			},
+		},
+		{
+			name: "subscription",
+			resource: Resource{Type: "google_pubsub_subscription", Name: "orders", Values: map[string]any{
+				"name": "shipping", "topic": "projects/acme/topics/order-created",
+			}},
+			expected: servicefile.Relationship{
+				Action: servicefile.RelationshipActionReceives, Participant: "Pub/Sub", Technology: "pubsub", External: true,
+				Channel: &servicefile.Channel{Name: "order-created"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rel, ok := Relationship(tt.resource)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, rel)
+		})
+	}
+
+	_, ok := Relationship(Resource{Type: "aws_iam_role"})
+	assert.False(t, ok)
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	orders := &servicefile.ServiceFile{
+		Info: servicefile.Info{Name: "orders"},
+		Relationships: []servicefile.Relationship{
+			{Action: servicefile.RelationshipActionUses, Participant: "PostgreSQL", Technology: "postgresql"},
+		},
+	}
+	billing := &servicefile.ServiceFile{Info: servicefile.Info{Name: "billing"}}
+	shipping := &servicefile.ServiceFile{
+		Info: servicefile.Info{Name: "shipping"},
+		Relationships: []servicefile.Relationship{
+			{
+				Action: servicefile.RelationshipActionReceives, Participant: "Broker", Description: "Ships orders",
+				Channel: &servicefile.Channel{Name: "order-created"},
+			},
+		},
+	}
+	serviceFiles := []*servicefile.ServiceFile{orders, billing, shipping}
+
+	state, err := Load(filepath.Join("testdata", "state.json"))
+	require.NoError(t, err)
+
+	plan, err := Load(filepath.Join("testdata", "plan.json"))
+	require.NoError(t, err)
+
+	results := Merge(serviceFiles, append(state.Resources(), plan.Resources()...), DefaultTagKeys)
+
+	reports := make([]string, 0, len(results))
+	for _, result := range results {
+		reports = append(reports, result.String())
+	}
+
+	assert.Equal(t, []string{
+		"orders: merged into uses orders-db from aws_db_instance.orders, matched by name orders-db",
+		"billing: added uses acme-invoices from aws_s3_bucket.invoices, matched by tag service=billing",
+		"aws_s3_bucket.logs: no service matched for uses acme-logs",
+		"orders: added receives SQS channel prod-orders-events from aws_sqs_queue.order_events, matched by name prod-orders-events",
+		"orders: added sends Pub/Sub channel order-created from google_pubsub_topic.orders, matched by tag app=orders",
+		"shipping: merged into receives Pub/Sub channel order-created from " +
+			"module.shipping.google_pubsub_subscription.orders, matched by name shipping-order-created",
+		"module.shipping.google_sql_database_instance.main: no service matched for uses main",
+	}, reports)
+
+	for _, result := range Merge(serviceFiles, state.Resources(), DefaultTagKeys) {
+		assert.True(t, result.Merged || result.Service == "", result.String())
+	}
+
+	assert.Equal(t, []servicefile.Relationship{
+		{
+			Action: servicefile.RelationshipActionReceives, Participant: "SQS", Technology: "sqs", External: true,
+			Channel:    &servicefile.Channel{Name: "prod-orders-events"},
+			Extensions: servicefile.Extensions{AddressExtension: "aws_sqs_queue.order_events"},
+		},
+		{
+			Action: servicefile.RelationshipActionSends, Participant: "Pub/Sub", Technology: "pubsub", External: true,
+			Channel:    &servicefile.Channel{Name: "order-created"},
+			Extensions: servicefile.Extensions{AddressExtension: "google_pubsub_topic.orders"},
+		},
+		{
+			Action: servicefile.RelationshipActionUses, Participant: "PostgreSQL", Technology: "postgresql", External: true,
+			Extensions: servicefile.Extensions{AddressExtension: "aws_db_instance.orders"},
+		},
+	}, orders.Relationships)
+
+	assert.Equal(t, []servicefile.Relationship{
+		{
+			Action: servicefile.RelationshipActionUses, Participant: "acme-invoices", Technology: "s3", External: true,
+			Extensions: servicefile.Extensions{AddressExtension: "aws_s3_bucket.invoices"},
+		},
+	}, billing.Relationships)
+
+	assert.Equal(t, []servicefile.Relationship{
+		{
+			Action: servicefile.RelationshipActionReceives, Participant: "Broker", Description: "Ships orders",
+			Technology: "pubsub", External: true, Channel: &servicefile.Channel{Name: "order-created"},
+			Extensions: servicefile.Extensions{AddressExtension: "module.shipping.google_pubsub_subscription.orders"},
+		},
+	}, shipping.Relationships)
+}
+
+func TestMergeSameEngine(t *testing.T) {
+	t.Parallel()
+
+	orders := &servicefile.ServiceFile{
+		Info: servicefile.Info{Name: "orders"},
+		Relationships: []servicefile.Relationship{
+			{Action: servicefile.RelationshipActionUses, Participant: "PostgreSQL", Technology: "postgresql"},
+		},
+	}
+
+	resources := []Resource{
+		{
+			Address: "aws_db_instance.orders", Mode: "managed", Type: "aws_db_instance", Name: "orders",
+			Values: map[string]any{"identifier": "orders-db", "engine": "postgres"},
+		},
+		{
+			Address: "aws_db_instance.analytics", Mode: "managed", Type: "aws_db_instance", Name: "analytics",
+			Values: map[string]any{"identifier": "orders-analytics", "engine": "postgres"},
+		},
+	}
+
+	for run := 0; run < 2; run++ {
+		results := Merge([]*servicefile.ServiceFile{orders}, resources, DefaultTagKeys)
+		require.Len(t, results, 2)
+
+		assert.True(t, results[0].Merged, results[0].String())
+		assert.Equal(t, run > 0, results[1].Merged, results[1].String())
+	}
+
+	assert.Equal(t, []servicefile.Relationship{
+		{
+			Action: servicefile.RelationshipActionUses, Participant: "PostgreSQL", Technology: "postgresql", External: true,
+			Extensions: servicefile.Extensions{AddressExtension: "aws_db_instance.orders"},
+		},
+		{
+			Action: servicefile.RelationshipActionUses, Participant: "orders-analytics", Technology: "postgresql", External: true,
+			Extensions: servicefile.Extensions{AddressExtension: "aws_db_instance.analytics"},
+		},
+	}, orders.Relationships)
+}
@@ -0,0 +1,168 @@
+// Package language provides the registry of programming languages servicefile annotations
+// are parsed from.
+//
+// A language only has to find the comments of its source files; annotations are read from
+// the comments the same way for all languages. Languages are registered by file extension,
+// usually from the init function of the package implementing them:
+//
+//	func init() {
+//		language.MustRegister(&Lua{})
+//	}
+//
+// Languages with C-like comments can describe them with a Syntax instead of scanning them.
+package language
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Comment is a comment found in a source file.
+type Comment struct {
+	// Text is the text of the comment written with Go comment markers: either a run of
+	// // lines or a /* */ block. Indentation following the markers is kept, as it delimits
+	// multi-line values.
+	Text string
+	// Declaration is the name of the type or function the comment documents, if any.
+	Declaration string
+}
+
+// Language finds the comments of the source files of a programming language.
+type Language interface {
+	// Name returns the name the language is selected by, e.g. rust.
+	Name() string
+	// Extensions returns the extensions of the source files of the language, e.g. .rs.
+	Extensions() []string
+	// Comments returns the comments of a source file.
+	Comments(path string, src []byte) ([]Comment, error)
+}
+
+// Registry holds languages by name and file extension.
+type Registry struct {
+	mu          sync.RWMutex
+	byName      map[string]Language
+	byExtension map[string]Language
+}
+
+// NewRegistry creates a registry holding the given languages.
+func NewRegistry(languages ...Language) (*Registry, error) {
+	r := &Registry{
+		byName:      make(map[string]Language),
+		byExtension: make(map[string]Language),
+	}
+
+	for _, lang := range languages {
+		if err := r.Register(lang); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Register adds a language to the registry. Registering a language with the name of
+// a registered language is an error; an extension already claimed by another language
+// is taken over by the new one, which allows replacing the handling of single extensions.
+func (r *Registry) Register(lang Language) error {
+	name := lang.Name()
+	if name == "" {
+		return fmt.Errorf("language name is required")
+	}
+
+	extensions := lang.Extensions()
+	if len(extensions) == 0 {
+		return fmt.Errorf("language %s has no file extensions", name)
+	}
+
+	for _, ext := range extensions {
+		if !strings.HasPrefix(ext, ".") {
+			return fmt.Errorf("language %s: extension %q must start with a dot", name, ext)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.byName[name]; exists {
+		return fmt.Errorf("language %s is already registered", name)
+	}
+
+	r.byName[name] = lang
+	for _, ext := range extensions {
+		r.byExtension[strings.ToLower(ext)] = lang
+	}
+
+	return nil
+}
+
+// Lookup returns the language handling files with the given extension, e.g. .rs.
+func (r *Registry) Lookup(ext string) (Language, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lang, ok := r.byExtension[strings.ToLower(ext)]
+
+	return lang, ok
+}
+
+// Get returns the language registered with the given name.
+func (r *Registry) Get(name string) (Language, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lang, ok := r.byName[name]
+
+	return lang, ok
+}
+
+// Extensions returns the extensions the language registered with the given name handles,
+// leaving out the ones taken over by other languages.
+func (r *Registry) Extensions(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var extensions []string
+	for ext, lang := range r.byExtension {
+		if lang.Name() == name {
+			extensions = append(extensions, ext)
+		}
+	}
+
+	sort.Strings(extensions)
+
+	return extensions
+}
+
+// Languages returns the registered languages sorted by name.
+func (r *Registry) Languages() []Language {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	languages := make([]Language, 0, len(r.byName))
+	for _, lang := range r.byName {
+		languages = append(languages, lang)
+	}
+
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].Name() < languages[j].Name()
+	})
+
+	return languages
+}
+
+// Default is the registry the servicefile parse command reads languages from.
+var Default, _ = NewRegistry()
+
+// Register adds a language to the default registry.
+func Register(lang Language) error {
+	return Default.Register(lang)
+}
+
+// MustRegister adds a language to the default registry and panics if it can't be registered.
+func MustRegister(lang Language) {
+	if err := Register(lang); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,100 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLanguage struct {
+	name       string
+	extensions []string
+}
+
+func (l testLanguage) Name() string {
+	return l.name
+}
+
+func (l testLanguage) Extensions() []string {
+	return l.extensions
+}
+
+func (l testLanguage) Comments(string, []byte) ([]Comment, error) {
+	return nil, nil
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	lua := testLanguage{name: "lua", extensions: []string{".lua"}}
+	js := testLanguage{name: "js", extensions: []string{".js", ".mjs"}}
+	flow := testLanguage{name: "flow", extensions: []string{".JS"}}
+
+	r, err := NewRegistry(lua, js)
+	require.NoError(t, err)
+
+	got, ok := r.Lookup(".lua")
+	require.True(t, ok)
+	assert.Equal(t, lua, got)
+
+	_, ok = r.Lookup(".rb")
+	assert.False(t, ok)
+
+	require.NoError(t, r.Register(flow))
+
+	got, ok = r.Lookup(".js")
+	require.True(t, ok)
+	assert.Equal(t, flow, got, "the extension is taken over by the last registered language")
+
+	assert.Equal(t, []string{".mjs"}, r.Extensions("js"))
+	assert.Equal(t, []string{".js"}, r.Extensions("flow"))
+
+	got, ok = r.Get("js")
+	require.True(t, ok)
+	assert.Equal(t, js, got)
+
+	assert.Equal(t, []Language{flow, js, lua}, r.Languages())
+}
+
+func TestRegistryRegisterErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		lang     Language
+		expected string
+	}{
+		{
+			name:     "duplicate name",
+			lang:     testLanguage{name: "lua", extensions: []string{".luau"}},
+			expected: "language lua is already registered",
+		},
+		{
+			name:     "no name",
+			lang:     testLanguage{extensions: []string{".x"}},
+			expected: "language name is required",
+		},
+		{
+			name:     "no extensions",
+			lang:     testLanguage{name: "x"},
+			expected: "language x has no file extensions",
+		},
+		{
+			name:     "extension without dot",
+			lang:     testLanguage{name: "x", extensions: []string{"x"}},
+			expected: `language x: extension "x" must start with a dot`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := NewRegistry(testLanguage{name: "lua", extensions: []string{".lua"}})
+			require.NoError(t, err)
+
+			assert.EqualError(t, r.Register(tt.lang), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package language
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Syntax describes the comments of a language well enough to find them, which covers most
+// languages with C-like comments. Runs of line comments are returned as a single comment and
+// block comments without the asterisks leading their lines, e.g. of Javadoc comments.
+//
+// Languages only describe their comment tokens, the literals comment tokens aren't recognized
+// in and their declarations:
+//
+//	var syntax = &language.Syntax{
+//		LineComment: "--",
+//		Literal:     skipString,
+//		Declaration: regexp.MustCompile(`^\s*(?:local\s+)?function\s+([\w.:]+)`),
+//	}
+type Syntax struct {
+	// LineComment starts a comment running to the end of the line, e.g. //.
+	LineComment string
+	// BlockStart and BlockEnd delimit block comments, e.g. /* and */. They are empty for
+	// languages without block comments.
+	BlockStart, BlockEnd string
+	// NestedBlocks is set when block comments may contain other block comments.
+	NestedBlocks bool
+	// LineText, if set, rewrites the text following the token of a line comment,
+	// e.g. to remove the markers of doc comments.
+	LineText func(text string) string
+	// Literal returns the index following the literal starting at i, e.g. a string, or i when
+	// none starts there. Comment tokens within literals are skipped.
+	Literal func(src string, i int) int
+	// Docstring, if set, reports whether a literal documenting a declaration starts at i, e.g.
+	// a Python docstring, and returns the index following it along with its comment.
+	Docstring func(src string, i int) (end int, doc Comment, ok bool)
+	// Declaration matches the declaration following a comment, its first group being the name.
+	Declaration *regexp.Regexp
+}
+
+// blockLinePrefix is the asterisk leading the lines of Javadoc style block comments.
+var blockLinePrefix = regexp.MustCompile(`^[ \t]*\*(?:[ \t]|$)`)
+
+// Comments returns the comments of the source.
+func (s *Syntax) Comments(src string) []Comment {
+	var (
+		comments []Comment
+		// group holds the line comments of the current run and lineEnd where the last one ended.
+		group   []string
+		lineEnd int
+	)
+
+	flush := func() {
+		if len(group) > 0 {
+			comments = append(comments, Comment{
+				Text:        strings.Join(group, "\n"),
+				Declaration: s.declarationAfter(src[lineEnd:]),
+			})
+			group = nil
+		}
+	}
+
+	for i := 0; i < len(src); {
+		switch {
+		case s.LineComment != "" && strings.HasPrefix(src[i:], s.LineComment):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += i
+			}
+
+			// A blank line or code between line comments starts a new comment.
+			if len(group) > 0 && (strings.Count(src[lineEnd:i], "\n") > 1 || strings.TrimSpace(src[lineEnd:i]) != "") {
+				flush()
+			}
+
+			text := strings.TrimRight(src[i+len(s.LineComment):end], "\r")
+			if s.LineText != nil {
+				text = s.LineText(text)
+			}
+
+			group = append(group, "//"+text)
+			lineEnd = end
+			i = end
+		case s.BlockStart != "" && strings.HasPrefix(src[i:], s.BlockStart):
+			flush()
+
+			end := s.skipBlock(src, i)
+
+			comments = append(comments, Comment{
+				Text:        s.normalizeBlock(src[i:end]),
+				Declaration: s.declarationAfter(src[end:]),
+			})
+			i = end
+		default:
+			if s.Docstring != nil {
+				if end, doc, ok := s.Docstring(src, i); ok {
+					flush()
+
+					comments = append(comments, doc)
+					i = end
+
+					continue
+				}
+			}
+
+			if s.Literal != nil {
+				if end := s.Literal(src, i); end > i {
+					i = end
+					continue
+				}
+			}
+
+			i++
+		}
+	}
+
+	flush()
+
+	return comments
+}
+
+// declarationAfter returns the name of the declaration the source starts with.
+func (s *Syntax) declarationAfter(src string) string {
+	if s.Declaration == nil {
+		return ""
+	}
+
+	if match := s.Declaration.FindStringSubmatch(src); match != nil {
+		return match[1]
+	}
+
+	return ""
+}
+
+// skipBlock returns the index following the block comment starting at i.
+func (s *Syntax) skipBlock(src string, i int) int {
+	depth := 0
+
+	for i < len(src) {
+		switch {
+		case strings.HasPrefix(src[i:], s.BlockStart):
+			if depth == 0 || s.NestedBlocks {
+				depth++
+			}
+			i += len(s.BlockStart)
+		case strings.HasPrefix(src[i:], s.BlockEnd):
+			depth--
+			i += len(s.BlockEnd)
+
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+
+	return len(src)
+}
+
+// normalizeBlock returns the block comment with /* */ markers, removing the * or ! marking
+// doc comments, e.g. /** and /*!, and the leading asterisks of the lines following the first
+// one, keeping the indentation following them.
+func (s *Syntax) normalizeBlock(block string) string {
+	block = strings.TrimPrefix(block, s.BlockStart)
+
+	block, closed := strings.CutSuffix(block, s.BlockEnd)
+	if strings.HasPrefix(block, "*") || strings.HasPrefix(block, "!") {
+		block = block[1:]
+	}
+
+	lines := strings.Split(block, "\n")
+	for i := 1; i < len(lines); i++ {
+		lines[i] = blockLinePrefix.ReplaceAllString(lines[i], "")
+	}
+
+	block = "/*" + strings.Join(lines, "\n")
+	if closed {
+		block += "*/"
+	}
+
+	return block
+}
+
+// SkipString returns the index following the string or character literal starting at i,
+// which ends at the quote it starts with or at the end of the line. A backslash escapes
+// the character following it.
+func SkipString(src string, i int) int {
+	quote := src[i]
+
+	for i++; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case quote, '\n':
+			return i + 1
+		}
+	}
+
+	return i
+}
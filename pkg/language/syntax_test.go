@@ -0,0 +1,136 @@
+package language
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyntaxComments(t *testing.T) {
+	t.Parallel()
+
+	cLike := &Syntax{
+		LineComment: "//",
+		BlockStart:  "/*",
+		BlockEnd:    "*/",
+		Literal: func(src string, i int) int {
+			if src[i] == '"' {
+				return SkipString(src, i)
+			}
+
+			return i
+		},
+		Declaration: regexp.MustCompile(`^\s*(?:@\w+\s*)*(?:class|func)\s+(\w+)`),
+	}
+
+	nested := *cLike
+	nested.NestedBlocks = true
+
+	doc := &Syntax{
+		LineComment: "#",
+		LineText:    strings.ToUpper,
+		Docstring: func(src string, i int) (int, Comment, bool) {
+			if !strings.HasPrefix(src[i:], "'''") {
+				return i, Comment{}, false
+			}
+
+			end := i + 3 + strings.Index(src[i+3:], "'''") + 3
+
+			return end, Comment{Text: "/*" + src[i+3:end-3] + "*/"}, true
+		},
+	}
+
+	tests := []struct {
+		name     string
+		syntax   *Syntax
+		src      string
+		expected []Comment
+	}{
+		{
+			name:   "line comments grouped until a blank line",
+			syntax: cLike,
+			src:    "// a\n// b\n\n// c\r\nfunc f() {}\n",
+			expected: []Comment{
+				{Text: "// a\n// b"},
+				{Text: "// c", Declaration: "f"},
+			},
+		},
+		{
+			name:   "code ends a group of line comments",
+			syntax: cLike,
+			src:    "// a\nclass A {} // b\n",
+			expected: []Comment{
+				{Text: "// a", Declaration: "A"},
+				{Text: "// b"},
+			},
+		},
+		{
+			name:   "doc block",
+			syntax: cLike,
+			src:    "/**\n * a: b\n *   c\n */\n@Component\nclass Service {}\n",
+			expected: []Comment{
+				{Text: "/*\na: b\n  c\n */", Declaration: "Service"},
+			},
+		},
+		{
+			name:   "bang doc block",
+			syntax: cLike,
+			src:    "/*! a */",
+			expected: []Comment{
+				{Text: "/* a */"},
+			},
+		},
+		{
+			name:   "unterminated block",
+			syntax: cLike,
+			src:    "/* a\n * b",
+			expected: []Comment{
+				{Text: "/* a\nb"},
+			},
+		},
+		{
+			name:   "block comments don't nest",
+			syntax: cLike,
+			src:    "/* a /* b */ class Kind {}\n",
+			expected: []Comment{
+				{Text: "/* a /* b */", Declaration: "Kind"},
+			},
+		},
+		{
+			name:   "nested block comments",
+			syntax: &nested,
+			src:    "/* a /* b */ c */ func handle() {}\n",
+			expected: []Comment{
+				{Text: "/* a /* b */ c */", Declaration: "handle"},
+			},
+		},
+		{
+			name:   "comment tokens in literals",
+			syntax: cLike,
+			src:    "x = \"// a \\\" /* b\"; // c\n",
+			expected: []Comment{
+				{Text: "// c"},
+			},
+		},
+		{
+			name:   "line text and docstrings",
+			syntax: doc,
+			src:    "# a\n# b\n'''c'''\n# d\n",
+			expected: []Comment{
+				{Text: "// A\n// B"},
+				{Text: "/*c*/"},
+				{Text: "// D"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.syntax.Comments(tt.src))
+		})
+	}
+}
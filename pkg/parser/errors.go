@@ -0,0 +1,19 @@
+package parser
+
+import "github.com/holydocs/servicefile/internal/parser"
+
+var (
+	// ErrNoServices is returned when no annotations are found.
+	ErrNoServices = parser.ErrNoServices
+	// ErrMixedPatterns is returned when some relationships name their service, service:name:action,
+	// while others don't, service:action.
+	ErrMixedPatterns = parser.ErrMixedPatterns
+)
+
+type (
+	// SourceError reports a source file that can't be read or parsed, e.g. because of a syntax error.
+	SourceError = parser.SourceError
+	// AnnotationError reports an invalid annotation, e.g. a channel on a uses relationship,
+	// and in strict mode an annotation with unknown fields or relationship actions.
+	AnnotationError = parser.AnnotationError
+)
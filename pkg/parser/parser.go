@@ -0,0 +1,130 @@
+// Package parser builds service files from the annotations found in source code.
+//
+// It is the library behind the servicefile parse command:
+//
+//	serviceFiles, err := parser.Parse(ctx, os.DirFS("./my-service"), parser.Exclude("vendor"))
+//
+// Go source is always parsed, other languages are looked up by file extension in a
+// language registry, see the language package.
+package parser
+
+import (
+	"context"
+	"io/fs"
+	"os"
+
+	"github.com/holydocs/servicefile/internal/parser"
+	"github.com/holydocs/servicefile/internal/parser/golang"
+	"github.com/holydocs/servicefile/pkg/language"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+
+	// Register the languages parsed from comments.
+	_ "github.com/holydocs/servicefile/internal/parser/csharp"
+	_ "github.com/holydocs/servicefile/internal/parser/jvm"
+	_ "github.com/holydocs/servicefile/internal/parser/python"
+	_ "github.com/holydocs/servicefile/internal/parser/rust"
+	_ "github.com/holydocs/servicefile/internal/parser/typescript"
+)
+
+// Option configures parsing.
+type Option func(o *options)
+
+type options struct {
+	config    parser.Config
+	gitDir    string
+	languages *language.Registry
+}
+
+// Dir sets the directory of the file system to parse, the root of the file system by default.
+func Dir(dir string) Option {
+	return func(o *options) {
+		o.config.Root = dir
+	}
+}
+
+// Recursive sets whether subdirectories are parsed, which they are by default.
+func Recursive(recursive bool) Option {
+	return func(o *options) {
+		o.config.Recursive = recursive
+	}
+}
+
+// DetectRepository fills the repository of services that don't declare one with the
+// URL of the origin remote of the git repository at dir on the disk. Failing to detect
+// the repository isn't an error.
+func DetectRepository(dir string) Option {
+	return func(o *options) {
+		o.gitDir = dir
+	}
+}
+
+// Include limits parsing to the files matching any of the patterns. Patterns use the
+// syntax of path.Match and are matched against both the slash-separated path relative
+// to the parsed directory and the file name, e.g. "*.go" or "internal/*/*.go".
+func Include(patterns ...string) Option {
+	return func(o *options) {
+		o.config.Include = append(o.config.Include, patterns...)
+	}
+}
+
+// Exclude skips the files and directories matching any of the patterns, see Include.
+func Exclude(patterns ...string) Option {
+	return func(o *options) {
+		o.config.Exclude = append(o.config.Exclude, patterns...)
+	}
+}
+
+// Strict makes annotations with unknown fields or relationship actions fail parsing
+// with an *AnnotationError instead of being ignored.
+func Strict() Option {
+	return func(o *options) {
+		o.config.Strict = true
+	}
+}
+
+// Languages sets the registry languages other than Go are looked up in, language.Default
+// by default. A language of the registry handling .go files replaces the Go parser.
+func Languages(registry *language.Registry) Option {
+	return func(o *options) {
+		o.languages = registry
+	}
+}
+
+// Parse builds the service files described by the annotations of the source files of fsys.
+// The paths of errors and of the files annotations refer to, e.g. OpenAPI documents, are
+// relative to fsys. Parsing stops with the error of ctx when it is done.
+func Parse(ctx context.Context, fsys fs.FS, opts ...Option) ([]*servicefile.ServiceFile, error) {
+	o := options{
+		config:    parser.Config{Recursive: true},
+		languages: language.Default,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	languages := []parser.Language{golang.NewCommentParser()}
+
+	for _, lang := range o.languages.Languages() {
+		// All the extensions of the language may be taken over by other languages.
+		if extensions := o.languages.Extensions(lang.Name()); len(extensions) > 0 {
+			languages = append(languages, parser.FromComments(lang, extensions...))
+		}
+	}
+
+	serviceFiles, err := parser.ParseFS(ctx, fsys, o.config, languages...)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.gitDir != "" {
+		parser.FillRepository(o.gitDir, serviceFiles)
+	}
+
+	return serviceFiles, nil
+}
+
+// ParseDir is Parse for a directory on the disk.
+func ParseDir(ctx context.Context, dir string, opts ...Option) ([]*servicefile.ServiceFile, error) {
+	return Parse(ctx, os.DirFS(dir), opts...)
+}
@@ -0,0 +1,283 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/holydocs/servicefile/pkg/language"
+	"github.com/holydocs/servicefile/pkg/servicefile"
+)
+
+var testFS = fstest.MapFS{
+	"main.go": {Data: []byte(`package main
+
+/*
+service:name Orders
+description: Manages orders
+*/
+`)},
+	"db/db.go": {Data: []byte(`package db
+
+// service:uses PostgreSQL
+// technology: postgresql
+type DB struct{}
+`)},
+	"api/openapi.yaml": {Data: []byte(`openapi: 3.0.0
+info:
+  title: Orders
+  version: 1.0.0
+paths:
+  /orders:
+    get: {}
+`)},
+	"api/server.ts": {Data: []byte(`// service:replies
+// spec: openapi.yaml
+export class Server {}
+`)},
+	"vendor/lib/lib.go": {Data: []byte(`package lib
+
+// service:uses Vendored
+type Lib struct{}
+`)},
+}
+
+func participants(sf *servicefile.ServiceFile) []string {
+	var result []string
+	for _, rel := range sf.Relationships {
+		result = append(result, string(rel.Action)+" "+rel.Participant)
+	}
+
+	return result
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{
+			name:     "all files",
+			expected: []string{"replies ", "uses PostgreSQL", "uses Vendored"},
+		},
+		{
+			name:     "exclude directory",
+			opts:     []Option{Exclude("vendor")},
+			expected: []string{"replies ", "uses PostgreSQL"},
+		},
+		{
+			name:     "include files",
+			opts:     []Option{Include("*.go"), Exclude("vendor/*")},
+			expected: []string{"uses PostgreSQL"},
+		},
+		{
+			name: "not recursive",
+			opts: []Option{Recursive(false)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			serviceFiles, err := Parse(context.Background(), testFS, tt.opts...)
+			require.NoError(t, err)
+			require.Len(t, serviceFiles, 1)
+
+			assert.Equal(t, "Orders", serviceFiles[0].Info.Name)
+			assert.Equal(t, tt.expected, participants(serviceFiles[0]))
+		})
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	t.Parallel()
+
+	serviceFiles, err := Parse(context.Background(), testFS, Exclude("vendor"))
+	require.NoError(t, err)
+	require.Len(t, serviceFiles, 1)
+
+	rel := serviceFiles[0].Relationships[0]
+	assert.Equal(t, "api/openapi.yaml", rel.Spec, "the spec is relative to the parsed directory")
+	assert.Equal(t, []string{"GET /orders"}, rel.Operations)
+}
+
+func TestParseDir(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"services/a/a.go": {Data: []byte("package a\n\n// service:name A\n")},
+		"services/b/b.go": {Data: []byte("package b\n\n// service:name B\n")},
+	}
+
+	serviceFiles, err := Parse(context.Background(), fsys, Dir("services/b"))
+	require.NoError(t, err)
+	require.Len(t, serviceFiles, 1)
+
+	assert.Equal(t, "B", serviceFiles[0].Info.Name)
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no services", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(context.Background(), fstest.MapFS{"README.md": {Data: []byte("service:name X")}})
+		assert.ErrorIs(t, err, ErrNoServices)
+	})
+
+	t.Run("mixed patterns", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(context.Background(), fstest.MapFS{
+			"a.go": {Data: []byte("package a\n\n// service:a:uses X\n\n// service:uses Y\n")},
+		})
+		assert.ErrorIs(t, err, ErrMixedPatterns)
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(context.Background(), fstest.MapFS{"a.go": {Data: []byte("package")}})
+
+		var sourceErr *SourceError
+		require.ErrorAs(t, err, &sourceErr)
+		assert.Equal(t, "a.go", sourceErr.Path)
+	})
+
+	t.Run("unparsable proto", func(t *testing.T) {
+		t.Parallel()
+
+		fsys := fstest.MapFS{
+			"main.go":                      {Data: []byte("package main\n\n// service:name Orders\n")},
+			"proto/health.proto":           {Data: []byte("service Health {\n  rpc Check(\n")},
+			"third_party/google/api.proto": {Data: []byte("service Broken {")},
+		}
+
+		serviceFiles, err := Parse(context.Background(), fsys)
+		require.NoError(t, err)
+		require.Len(t, serviceFiles, 1)
+
+		fsys["server.go"] = &fstest.MapFile{Data: []byte(`package main
+
+// service:replies
+type Server struct {
+	UnimplementedHealthServer
+}
+`)}
+
+		_, err = Parse(context.Background(), fsys)
+
+		var sourceErr *SourceError
+		require.ErrorAs(t, err, &sourceErr)
+		assert.Equal(t, "proto/health.proto", sourceErr.Path)
+	})
+
+	t.Run("invalid relationship", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(context.Background(), fstest.MapFS{
+			"a.go": {Data: []byte("package a\n\n// service:uses X\n// channel: events\n")},
+		})
+
+		var annotationErr *AnnotationError
+		require.ErrorAs(t, err, &annotationErr)
+		assert.Equal(t, "a.go", annotationErr.Position)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		t.Parallel()
+
+		fsys := fstest.MapFS{
+			"a.go": {Data: []byte("package a\n\n// service:name A\n// descripton: typo\n\n// service:calls B\n")},
+		}
+
+		_, err := Parse(context.Background(), fsys)
+		require.NoError(t, err)
+
+		_, err = Parse(context.Background(), fsys, Strict())
+
+		var annotationErr *AnnotationError
+		require.ErrorAs(t, err, &annotationErr)
+		assert.Contains(t, err.Error(), `unknown field "descripton"`)
+		assert.Contains(t, err.Error(), `unknown action "calls"`)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(context.Background(), testFS, Exclude("["))
+		assert.Error(t, err)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := Parse(ctx, testFS)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// luaLanguage finds -- comments.
+type luaLanguage struct{}
+
+func (luaLanguage) Name() string {
+	return "lua"
+}
+
+func (luaLanguage) Extensions() []string {
+	return []string{".lua"}
+}
+
+func (luaLanguage) Comments(_ string, src []byte) ([]language.Comment, error) {
+	var lines []string
+	for _, line := range strings.Split(string(src), "\n") {
+		if text, ok := strings.CutPrefix(line, "--"); ok {
+			lines = append(lines, "//"+text)
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("no comments")
+	}
+
+	return []language.Comment{{Text: strings.Join(lines, "\n")}}, nil
+}
+
+func TestParseLanguages(t *testing.T) {
+	t.Parallel()
+
+	registry, err := language.NewRegistry(luaLanguage{})
+	require.NoError(t, err)
+
+	fsys := fstest.MapFS{
+		"main.lua":  {Data: []byte("-- service:name Game\n-- description: Plays\n")},
+		"index.ts":  {Data: []byte("// service:uses Ignored\n")},
+		"script.go": {Data: []byte("package main\n\n// service:uses Redis\n")},
+	}
+
+	serviceFiles, err := Parse(context.Background(), fsys, Languages(registry))
+	require.NoError(t, err)
+	require.Len(t, serviceFiles, 1)
+
+	assert.Equal(t, "Game", serviceFiles[0].Info.Name)
+	assert.Equal(t, []string{"uses Redis"}, participants(serviceFiles[0]))
+
+	_, err = Parse(context.Background(), fstest.MapFS{"empty.lua": {}}, Languages(registry))
+
+	var sourceErr *SourceError
+	require.ErrorAs(t, err, &sourceErr)
+	assert.Equal(t, "empty.lua", sourceErr.Path)
+}
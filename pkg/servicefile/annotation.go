@@ -0,0 +1,208 @@
+package servicefile
+
+import "gopkg.in/yaml.v3"
+
+// Typed annotations are an alternative to comment annotations which are checked
+// by the compiler and discoverable by IDEs. They are declared as package-level
+// variables and read by the parser from the source code, e.g.:
+//
+//	var _ = servicefile.Service("UserService", servicefile.Description("Handles users"))
+//
+//	var _ = servicefile.Uses("PostgreSQL",
+//		servicefile.Technology("postgresql"),
+//		servicefile.Proto("tcp"),
+//	)
+//
+// Arguments must be string literals, since they are read without compiling the code.
+
+// Option sets a field of a typed annotation.
+type Option func(info *Info, rel *Relationship)
+
+// Service declares the service.
+func Service(name string, opts ...Option) Info {
+	info := Info{Name: name}
+
+	var rel Relationship
+	for _, opt := range opts {
+		opt(&info, &rel)
+	}
+
+	return info
+}
+
+// Uses declares that the service depends on a participant.
+func Uses(participant string, opts ...Option) Relationship {
+	return newRelationship(RelationshipActionUses, participant, opts)
+}
+
+// Requests declares that the service makes requests to a participant.
+func Requests(participant string, opts ...Option) Relationship {
+	return newRelationship(RelationshipActionRequests, participant, opts)
+}
+
+// Replies declares that the service provides APIs for a participant.
+// The participant may be empty.
+func Replies(participant string, opts ...Option) Relationship {
+	return newRelationship(RelationshipActionReplies, participant, opts)
+}
+
+// Sends declares that the service sends messages to a participant.
+func Sends(participant string, opts ...Option) Relationship {
+	return newRelationship(RelationshipActionSends, participant, opts)
+}
+
+// Receives declares that the service receives messages from a participant.
+func Receives(participant string, opts ...Option) Relationship {
+	return newRelationship(RelationshipActionReceives, participant, opts)
+}
+
+func newRelationship(action RelationshipAction, participant string, opts []Option) Relationship {
+	rel := Relationship{
+		Action:      action,
+		Participant: participant,
+	}
+
+	var info Info
+	for _, opt := range opts {
+		opt(&info, &rel)
+	}
+
+	return rel
+}
+
+// For attaches a relationship to the named service,
+// the typed equivalent of the service:{service_name}:{action} form.
+func For(string) Option {
+	return func(*Info, *Relationship) {}
+}
+
+// Description sets the description of a service or a relationship.
+func Description(description string) Option {
+	return func(info *Info, rel *Relationship) {
+		info.Description = description
+		rel.Description = description
+	}
+}
+
+// Tags sets the tags of a service or a relationship.
+func Tags(tags ...string) Option {
+	return func(info *Info, rel *Relationship) {
+		info.Tags = tags
+		rel.Tags = tags
+	}
+}
+
+// System sets the system of a service.
+func System(system string) Option {
+	return func(info *Info, _ *Relationship) {
+		info.System = system
+	}
+}
+
+// Owner sets the owner of a service.
+func Owner(owner string) Option {
+	return func(info *Info, _ *Relationship) {
+		info.Owner = owner
+	}
+}
+
+// Repository sets the repository of a service.
+func Repository(repository string) Option {
+	return func(info *Info, _ *Relationship) {
+		info.Repository = repository
+	}
+}
+
+// Technology sets the technology of a relationship.
+func Technology(technology string) Option {
+	return func(_ *Info, rel *Relationship) {
+		rel.Technology = technology
+	}
+}
+
+// Proto sets the protocol of a relationship.
+func Proto(proto string) Option {
+	return func(_ *Info, rel *Relationship) {
+		rel.Proto = proto
+	}
+}
+
+// External marks a relationship as an external dependency.
+func External() Option {
+	return func(_ *Info, rel *Relationship) {
+		rel.External = true
+	}
+}
+
+// Person marks a relationship as one with a person.
+func Person() Option {
+	return func(_ *Info, rel *Relationship) {
+		rel.Person = true
+	}
+}
+
+// ChannelName sets the channel name of a sends or receives relationship.
+func ChannelName(name string) Option {
+	return func(_ *Info, rel *Relationship) {
+		relationshipChannel(rel).Name = name
+	}
+}
+
+// Messages sets the message names of a sends or receives relationship.
+func Messages(messages ...string) Option {
+	return func(_ *Info, rel *Relationship) {
+		relationshipChannel(rel).Messages = messages
+	}
+}
+
+// Schema sets the payload schema reference of a sends or receives relationship.
+func Schema(schema string) Option {
+	return func(_ *Info, rel *Relationship) {
+		relationshipChannel(rel).Schema = schema
+	}
+}
+
+// Operations sets the operations of a replies or requests relationship.
+func Operations(operations ...string) Option {
+	return func(_ *Info, rel *Relationship) {
+		rel.Operations = operations
+	}
+}
+
+// Spec sets the path of an OpenAPI document describing the API of a replies relationship.
+// The path is relative to the annotated source file.
+func Spec(path string) Option {
+	return func(_ *Info, rel *Relationship) {
+		rel.Spec = path
+	}
+}
+
+func relationshipChannel(rel *Relationship) *Channel {
+	if rel.Channel == nil {
+		rel.Channel = &Channel{}
+	}
+
+	return rel.Channel
+}
+
+// Extension sets an x-* extension field of a service or a relationship.
+// The value is decoded as YAML, so numbers and booleans keep their types.
+func Extension(key, value string) Option {
+	return func(info *Info, rel *Relationship) {
+		var v any
+		if err := yaml.Unmarshal([]byte(value), &v); err != nil || v == nil {
+			v = value
+		}
+
+		if info.Extensions == nil {
+			info.Extensions = make(Extensions)
+		}
+
+		if rel.Extensions == nil {
+			rel.Extensions = make(Extensions)
+		}
+
+		info.Extensions[key] = v
+		rel.Extensions[key] = v
+	}
+}
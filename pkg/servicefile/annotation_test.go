@@ -0,0 +1,62 @@
+package servicefile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedAnnotations(t *testing.T) {
+	t.Parallel()
+
+	info := Service("Orders",
+		Description("Handles orders"),
+		System("shop"),
+		Owner("team-orders"),
+		Repository("https://github.com/holydocs/orders"),
+		Tags("orders", "core"),
+	)
+
+	assert.Equal(t, Info{
+		Name:        "Orders",
+		Description: "Handles orders",
+		System:      "shop",
+		Owner:       "team-orders",
+		Repository:  "https://github.com/holydocs/orders",
+		Tags:        []string{"orders", "core"},
+	}, info)
+
+	rel := Requests("Stripe",
+		For("Orders"),
+		Description("Charges cards"),
+		Technology("stripe"),
+		Proto("http"),
+		Tags("payments"),
+		External(),
+	)
+
+	assert.Equal(t, Relationship{
+		Action:      RelationshipActionRequests,
+		Participant: "Stripe",
+		Description: "Charges cards",
+		Technology:  "stripe",
+		Proto:       "http",
+		Tags:        []string{"payments"},
+		External:    true,
+	}, rel)
+
+	assert.Equal(t, Relationship{Action: RelationshipActionReplies, Participant: "User", Person: true}, Replies("User", Person()))
+	assert.Equal(t, []string{"GET /users", "POST /users"}, Replies("", Operations("GET /users", "POST /users")).Operations)
+	assert.Equal(t, "api/openapi.yaml", Replies("", Spec("api/openapi.yaml")).Spec)
+	assert.Equal(t, RelationshipAction(RelationshipActionUses), Uses("PostgreSQL").Action)
+	assert.Equal(t, Relationship{
+		Action:      RelationshipActionSends,
+		Participant: "Kafka",
+		Channel: &Channel{
+			Name:     "orders.events",
+			Messages: []string{"OrderCreated"},
+			Schema:   "schemas/order.avsc",
+		},
+	}, Sends("Kafka", ChannelName("orders.events"), Messages("OrderCreated"), Schema("schemas/order.avsc")))
+	assert.Equal(t, RelationshipAction(RelationshipActionReceives), Receives("Kafka").Action)
+}
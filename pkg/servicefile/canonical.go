@@ -0,0 +1,255 @@
+package servicefile
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Canonical returns the canonical form of a YAML service file document, keeping its comments:
+//   - fields are in the order of the specification, followed by the x-* fields sorted by name
+//     and by unknown fields in their original order,
+//   - relationships are sorted like Sort does, tags are sorted and deduplicated,
+//   - booleans are written true or false, empty optional fields are removed and empty
+//     required fields are written "",
+//   - the collections of the specification are in block style, strings are quoted only
+//     when needed.
+//
+// Extension values are kept as they are and the document isn't migrated.
+func Canonical(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	// Empty documents, or holding comments only, are canonical.
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		return data, nil
+	}
+
+	var sf ServiceFile
+	if err := doc.Decode(&sf); err != nil {
+		return nil, err
+	}
+
+	root := doc.Content[0]
+
+	// The comment on top of the document stays on top when its first field is moved.
+	var first *yaml.Node
+	if root.Kind == yaml.MappingNode && len(root.Content) > 0 {
+		first = root.Content[0]
+	}
+
+	canonicalize(root, reflect.TypeOf(sf))
+
+	if first != nil && first != root.Content[0] && first.HeadComment != "" {
+		doc.HeadComment = joinComments(doc.HeadComment, first.HeadComment)
+		first.HeadComment = ""
+	}
+
+	return yaml.Marshal(&doc)
+}
+
+// canonicalize rewrites the node of a value of type typ in canonical form.
+func canonicalize(node *yaml.Node, typ reflect.Type) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		node.Style &^= yaml.FlowStyle
+		canonicalizeMapping(node, typ)
+	case typ.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		node.Style &^= yaml.FlowStyle
+
+		for _, item := range node.Content {
+			canonicalize(item, typ.Elem())
+		}
+
+		if typ.Elem() == reflect.TypeOf(Relationship{}) {
+			sortRelationships(node)
+		}
+	case typ.Kind() == reflect.Bool && node.Kind == yaml.ScalarNode:
+		var b bool
+		if err := node.Decode(&b); err == nil {
+			node.Value, node.Tag, node.Style = strconv.FormatBool(b), "!!bool", 0
+		}
+	case node.Kind == yaml.ScalarNode:
+		node.Style &^= yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+	}
+}
+
+// specField represents a field of the specification.
+type specField struct {
+	typ       reflect.Type
+	omitempty bool
+}
+
+func canonicalizeMapping(node *yaml.Node, typ reflect.Type) {
+	var (
+		names  []string
+		fields = make(map[string]specField)
+	)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		names = append(names, name)
+		fields[name] = specField{typ: field.Type, omitempty: options == "omitempty"}
+	}
+
+	_, extensible := typ.FieldByName("Extensions")
+
+	var (
+		known              = make(map[string][]*yaml.Node)
+		extensions, others [][]*yaml.Node
+		// removed holds the comments of the removed fields, moved to the next field.
+		removed []string
+	)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if len(removed) > 0 {
+			key.HeadComment = joinComments(append(removed, key.HeadComment)...)
+			removed = nil
+		}
+
+		field, ok := fields[key.Value]
+
+		switch {
+		case ok:
+			canonicalize(value, field.typ)
+
+			if isEmptyNode(value) {
+				if field.omitempty {
+					removed = append(removed, key.HeadComment, key.LineComment, value.LineComment)
+					continue
+				}
+
+				setEmpty(value, field.typ)
+			}
+
+			if key.Value == "tags" {
+				sortTags(value)
+			}
+
+			known[key.Value] = []*yaml.Node{key, value}
+		case extensible && IsExtension(key.Value):
+			extensions = append(extensions, []*yaml.Node{key, value})
+		default:
+			others = append(others, []*yaml.Node{key, value})
+		}
+	}
+
+	if len(removed) > 0 {
+		node.FootComment = joinComments(append([]string{node.FootComment}, removed...)...)
+	}
+
+	sort.SliceStable(extensions, func(i, j int) bool {
+		return extensions[i][0].Value < extensions[j][0].Value
+	})
+
+	content := make([]*yaml.Node, 0, len(node.Content))
+
+	for _, name := range names {
+		content = append(content, known[name]...)
+	}
+
+	for _, pair := range append(extensions, others...) {
+		content = append(content, pair...)
+	}
+
+	node.Content = content
+}
+
+// isEmptyNode reports whether the node is null, an empty string, false or an empty collection.
+func isEmptyNode(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Tag == "!!null" || node.Value == "" || node.Tag == "!!bool" && node.Value == "false"
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(node.Content) == 0
+	}
+
+	return false
+}
+
+// setEmpty sets null values of required fields to the empty value of their type.
+func setEmpty(node *yaml.Node, typ reflect.Type) {
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!null" {
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		node.Value, node.Tag, node.Style = "", "!!str", 0
+	case reflect.Slice:
+		node.Kind, node.Value, node.Tag, node.Style = yaml.SequenceNode, "", "!!seq", 0
+	}
+}
+
+// sortTags sorts the items of the sequence node and removes the duplicates.
+func sortTags(node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		return
+	}
+
+	sort.SliceStable(node.Content, func(i, j int) bool {
+		return node.Content[i].Value < node.Content[j].Value
+	})
+
+	tags := node.Content[:0]
+
+	for i, tag := range node.Content {
+		if i > 0 && tag.Kind == yaml.ScalarNode && tag.Value == tags[len(tags)-1].Value {
+			continue
+		}
+
+		tags = append(tags, tag)
+	}
+
+	node.Content = tags
+}
+
+// sortRelationships sorts the items of the sequence node like Sort does.
+func sortRelationships(node *yaml.Node) {
+	relationships := make(map[*yaml.Node]Relationship, len(node.Content))
+
+	for _, item := range node.Content {
+		var r Relationship
+		_ = item.Decode(&r)
+
+		relationships[item] = r
+	}
+
+	sort.SliceStable(node.Content, func(i, j int) bool {
+		return relationships[node.Content[i]].less(relationships[node.Content[j]])
+	})
+}
+
+// joinComments joins the non-empty comments with new lines.
+func joinComments(comments ...string) string {
+	var joined []string
+
+	for _, comment := range comments {
+		if comment != "" {
+			joined = append(joined, comment)
+		}
+	}
+
+	return strings.Join(joined, "\n")
+}
@@ -0,0 +1,120 @@
+package servicefile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestCanonical(t *testing.T) {
+	t.Parallel()
+
+	input := `# Orders service.
+info:
+  # Nobody owns it yet.
+  owner: ""
+  tags: [payments, core, payments]
+  x-tier: 1
+  name: "orders"
+  description: |
+    Manages orders
+    and payments.
+  x-regions: [eu, us]
+  system: ~
+servicefile: "0.2.0"
+relationships:
+  # Database.
+  - action: uses
+    participant: PostgreSQL
+    technology: "true"
+    description:
+    external: yes
+    custom: 1
+  - technology: kafka # broker
+    action: sends
+    participant: Kafka
+    person: False
+    channel: {name: orders.events, messages: [OrderCreated]}
+`
+
+	want := `# Orders service.
+
+servicefile: 0.2.0
+info:
+    name: orders
+    description: |
+        Manages orders
+        and payments.
+    # Nobody owns it yet.
+    tags:
+        - core
+        - payments
+    x-regions: [eu, us]
+    x-tier: 1
+relationships:
+    - action: sends
+      participant: Kafka
+      technology: kafka # broker
+      channel:
+        name: orders.events
+        messages:
+            - OrderCreated
+    # Database.
+    - action: uses
+      participant: PostgreSQL
+      technology: "true"
+      external: true
+      custom: 1
+`
+
+	got, err := Canonical([]byte(input))
+	require.NoError(t, err)
+	assert.Equal(t, want, string(got))
+
+	again, err := Canonical(got)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(again), "canonical documents are left as they are")
+
+	var before, after ServiceFile
+	require.NoError(t, yaml.Unmarshal([]byte(input), &before))
+	require.NoError(t, yaml.Unmarshal(got, &after))
+
+	before.Sort()
+	before.Info.Tags = []string{"core", "payments"}
+	assert.Equal(t, before, after)
+}
+
+func TestCanonicalMarshaled(t *testing.T) {
+	t.Parallel()
+
+	sf := testServiceFile()
+	sf.Sort()
+
+	data, err := sf.Marshal(FormatYAML)
+	require.NoError(t, err)
+
+	got, err := Canonical(data)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(got))
+}
+
+func TestCanonicalRequiredFields(t *testing.T) {
+	t.Parallel()
+
+	got, err := Canonical([]byte("info:\n  name: orders\n  description:\nrelationships:\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "info:\n    name: orders\n    description: \"\"\nrelationships: []\n", string(got))
+}
+
+func TestCanonicalInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := Canonical([]byte("info: [orders]\n"))
+	assert.Error(t, err)
+
+	got, err := Canonical([]byte("# Nothing yet.\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Nothing yet.\n", string(got))
+}
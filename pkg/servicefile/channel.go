@@ -0,0 +1,126 @@
+package servicefile
+
+import "sort"
+
+// IsAsync reports whether the action is sending or receiving messages.
+func (a RelationshipAction) IsAsync() bool {
+	return a == RelationshipActionSends || a == RelationshipActionReceives
+}
+
+func (c *Channel) name() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.Name
+}
+
+// Flow represents messages flowing through a channel from a producer to a consumer.
+type Flow struct {
+	Channel    string
+	Technology string
+	Producer   string
+	Consumer   string
+	// Messages are the messages sent by the producer that the consumer receives.
+	// It's empty when neither side lists its messages.
+	Messages []string
+}
+
+// MatchChannels matches sends relationships with receives relationships of the
+// given service files by channel name. Relationships using different technologies
+// don't match, unless one of them doesn't set it.
+func MatchChannels(serviceFiles []*ServiceFile) []Flow {
+	type endpoint struct {
+		service string
+		rel     Relationship
+	}
+
+	var producers, consumers []endpoint
+
+	for _, sf := range serviceFiles {
+		for _, rel := range sf.Relationships {
+			if rel.Channel == nil || rel.Channel.Name == "" {
+				continue
+			}
+
+			switch rel.Action {
+			case RelationshipActionSends:
+				producers = append(producers, endpoint{service: sf.Info.Name, rel: rel})
+			case RelationshipActionReceives:
+				consumers = append(consumers, endpoint{service: sf.Info.Name, rel: rel})
+			}
+		}
+	}
+
+	var flows []Flow
+
+	for _, p := range producers {
+		for _, c := range consumers {
+			if p.rel.Channel.Name != c.rel.Channel.Name {
+				continue
+			}
+
+			if p.rel.Technology != "" && c.rel.Technology != "" && p.rel.Technology != c.rel.Technology {
+				continue
+			}
+
+			messages, ok := matchMessages(p.rel.Channel.Messages, c.rel.Channel.Messages)
+			if !ok {
+				continue
+			}
+
+			technology := p.rel.Technology
+			if technology == "" {
+				technology = c.rel.Technology
+			}
+
+			flows = append(flows, Flow{
+				Channel:    p.rel.Channel.Name,
+				Technology: technology,
+				Producer:   p.service,
+				Consumer:   c.service,
+				Messages:   messages,
+			})
+		}
+	}
+
+	sort.SliceStable(flows, func(i, j int) bool {
+		if flows[i].Channel != flows[j].Channel {
+			return flows[i].Channel < flows[j].Channel
+		}
+
+		if flows[i].Producer != flows[j].Producer {
+			return flows[i].Producer < flows[j].Producer
+		}
+
+		return flows[i].Consumer < flows[j].Consumer
+	})
+
+	return flows
+}
+
+// matchMessages returns the messages both sides have in common.
+// A side that doesn't list its messages matches all messages of the other side.
+func matchMessages(sent, received []string) ([]string, bool) {
+	if len(received) == 0 {
+		return sent, true
+	}
+
+	if len(sent) == 0 {
+		return received, true
+	}
+
+	receivedSet := make(map[string]bool, len(received))
+	for _, m := range received {
+		receivedSet[m] = true
+	}
+
+	var common []string
+	for _, m := range sent {
+		if receivedSet[m] {
+			common = append(common, m)
+		}
+	}
+
+	return common, len(common) > 0
+}
@@ -0,0 +1,108 @@
+package servicefile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchChannels(t *testing.T) {
+	t.Parallel()
+
+	serviceFiles := []*ServiceFile{
+		{
+			Info: Info{Name: "orders"},
+			Relationships: []Relationship{
+				{
+					Action:      RelationshipActionSends,
+					Participant: "Kafka",
+					Technology:  "kafka",
+					Channel:     &Channel{Name: "orders.events", Messages: []string{"OrderCreated", "OrderCancelled"}},
+				},
+				{
+					Action:      RelationshipActionSends,
+					Participant: "Kafka",
+					Technology:  "kafka",
+					Channel:     &Channel{Name: "audit"},
+				},
+				{
+					Action:      RelationshipActionUses,
+					Participant: "PostgreSQL",
+				},
+			},
+		},
+		{
+			Info: Info{Name: "billing"},
+			Relationships: []Relationship{
+				{
+					Action:      RelationshipActionReceives,
+					Participant: "Kafka",
+					Technology:  "kafka",
+					Channel:     &Channel{Name: "orders.events", Messages: []string{"OrderCreated"}},
+				},
+			},
+		},
+		{
+			Info: Info{Name: "notifications"},
+			Relationships: []Relationship{
+				{
+					Action:      RelationshipActionReceives,
+					Participant: "Kafka",
+					Channel:     &Channel{Name: "orders.events"},
+				},
+				{
+					Action:      RelationshipActionReceives,
+					Participant: "RabbitMQ",
+					Technology:  "rabbitmq",
+					Channel:     &Channel{Name: "audit"},
+				},
+			},
+		},
+		{
+			Info: Info{Name: "shipping"},
+			Relationships: []Relationship{
+				{
+					Action:      RelationshipActionReceives,
+					Participant: "Kafka",
+					Technology:  "kafka",
+					Channel:     &Channel{Name: "orders.events", Messages: []string{"OrderShipped"}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []Flow{
+		{
+			Channel:    "orders.events",
+			Technology: "kafka",
+			Producer:   "orders",
+			Consumer:   "billing",
+			Messages:   []string{"OrderCreated"},
+		},
+		{
+			Channel:    "orders.events",
+			Technology: "kafka",
+			Producer:   "orders",
+			Consumer:   "notifications",
+			Messages:   []string{"OrderCreated", "OrderCancelled"},
+		},
+	}, MatchChannels(serviceFiles))
+}
+
+func TestSortByChannel(t *testing.T) {
+	t.Parallel()
+
+	sf := &ServiceFile{
+		Relationships: []Relationship{
+			{Action: RelationshipActionSends, Participant: "Kafka", Channel: &Channel{Name: "orders"}},
+			{Action: RelationshipActionSends, Participant: "Kafka", Channel: &Channel{Name: "audit"}},
+			{Action: RelationshipActionSends, Participant: "Kafka"},
+		},
+	}
+
+	sf.Sort()
+
+	assert.Nil(t, sf.Relationships[0].Channel)
+	assert.Equal(t, "audit", sf.Relationships[1].Channel.Name)
+	assert.Equal(t, "orders", sf.Relationships[2].Channel.Name)
+}
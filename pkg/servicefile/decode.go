@@ -0,0 +1,215 @@
+package servicefile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Stdin is the path Load and LoadStrict read the standard input from.
+const Stdin = "-"
+
+// UnknownFieldError reports a field of a document that isn't part of the specification.
+type UnknownFieldError struct {
+	// Line and Column are zero for TOML documents.
+	Line   int
+	Column int
+	Field  string
+	// Suggestion is the known field closest to Field, empty if none is close enough.
+	Suggestion string
+}
+
+func (e *UnknownFieldError) Error() string {
+	msg := fmt.Sprintf("unknown field %q", e.Field)
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %q?", e.Suggestion)
+	}
+
+	if e.Line == 0 {
+		return msg
+	}
+
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, msg)
+}
+
+// Decoder reads service files from an input stream.
+type Decoder struct {
+	r           io.Reader
+	format      Format
+	knownFields bool
+}
+
+// NewDecoder returns a decoder reading YAML documents from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r, format: FormatYAML}
+}
+
+// Format sets the format of the documents, YAML by default.
+func (d *Decoder) Format(format Format) {
+	d.format = format
+}
+
+// KnownFields makes Decode fail on fields that aren't part of the specification, other
+// than x-* extension fields of the info and relationships, with an UnknownFieldError
+// for each of them.
+func (d *Decoder) KnownFields(enable bool) {
+	d.knownFields = enable
+}
+
+// Decode reads a service file, migrating documents of earlier versions to Version.
+func (d *Decoder) Decode(sf *ServiceFile) error {
+	data, err := io.ReadAll(d.r)
+	if err != nil {
+		return err
+	}
+
+	doc, err := UnmarshalNode(data, d.format)
+	if err != nil {
+		return err
+	}
+
+	if _, err := Migrate(doc); err != nil {
+		return err
+	}
+
+	if d.knownFields {
+		if err := checkFields(doc, reflect.TypeOf(ServiceFile{})); err != nil {
+			return err
+		}
+	}
+
+	return doc.Decode(sf)
+}
+
+// checkFields reports the keys of the mappings of the node that aren't fields of typ.
+func checkFields(node *yaml.Node, typ reflect.Type) error {
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice {
+		if typ.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode {
+			var errs []error
+			for _, item := range node.Content {
+				errs = append(errs, checkFields(item, typ.Elem()))
+			}
+
+			return errors.Join(errs...)
+		}
+
+		typ = typ.Elem()
+	}
+
+	if node.Kind == yaml.DocumentNode {
+		var errs []error
+		for _, content := range node.Content {
+			errs = append(errs, checkFields(content, typ))
+		}
+
+		return errors.Join(errs...)
+	}
+
+	if typ.Kind() != reflect.Struct || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	fields := yamlFields(typ)
+	_, extensible := typ.FieldByName("Extensions")
+
+	var errs []error
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+
+		if field, ok := fields[key.Value]; ok {
+			errs = append(errs, checkFields(node.Content[i+1], field.Type))
+			continue
+		}
+
+		if extensible && IsExtension(key.Value) {
+			continue
+		}
+
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+
+		errs = append(errs, &UnknownFieldError{
+			Line:       key.Line,
+			Column:     key.Column,
+			Field:      key.Value,
+			Suggestion: closest(key.Value, names),
+		})
+	}
+
+	return errors.Join(errs...)
+}
+
+// yamlFields maps the YAML field names of a struct to their fields.
+func yamlFields(typ reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		fields[name] = field
+	}
+
+	return fields
+}
+
+// closest returns the name closest to s, or an empty string if none is close enough to be
+// a typo of s. Names s starts with, or starting with s, are close too, e.g. proto for protocol.
+func closest(s string, names []string) string {
+	s = strings.ToLower(s)
+	best, bestDistance := "", 0
+
+	for _, name := range names {
+		d := distance(s, name)
+
+		near := d <= max(2, len(name)/3) ||
+			min(len(s), len(name)) >= 3 && (strings.HasPrefix(s, name) || strings.HasPrefix(name, s))
+		if near && (best == "" || d < bestDistance || d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+
+	return best
+}
+
+// distance returns the Levenshtein distance of a and b.
+func distance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
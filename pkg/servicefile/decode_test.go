@@ -0,0 +1,97 @@
+package servicefile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const typoDocument = `servicefile: 0.2.0
+info:
+  name: orders
+  descripton: Manages orders
+  x-tier: 1
+relationship:
+  - action: uses
+relationships:
+  - action: uses
+    partcipant: PostgreSQL
+    technology: postgresql
+    x-criticality: high
+  - action: sends
+    participant: Kafka
+    channel:
+      nmae: orders.events
+      messages: [OrderCreated]
+    unrelated: true
+`
+
+func TestDecoderKnownFields(t *testing.T) {
+	t.Parallel()
+
+	var sf ServiceFile
+	require.NoError(t, NewDecoder(strings.NewReader(typoDocument)).Decode(&sf))
+	assert.Equal(t, "orders", sf.Info.Name)
+	assert.Empty(t, sf.Info.Description)
+
+	decoder := NewDecoder(strings.NewReader(typoDocument))
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(&sf)
+	require.Error(t, err)
+
+	assert.Equal(t, []string{
+		`line 4, column 3: unknown field "descripton", did you mean "description"?`,
+		`line 6, column 1: unknown field "relationship", did you mean "relationships"?`,
+		`line 10, column 5: unknown field "partcipant", did you mean "participant"?`,
+		`line 16, column 7: unknown field "nmae", did you mean "name"?`,
+		`line 18, column 5: unknown field "unrelated"`,
+	}, strings.Split(err.Error(), "\n"))
+
+	var fieldErr *UnknownFieldError
+	require.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, &UnknownFieldError{Line: 4, Column: 3, Field: "descripton", Suggestion: "description"}, fieldErr)
+}
+
+func TestDecoderTopLevelExtension(t *testing.T) {
+	t.Parallel()
+
+	decoder := NewDecoder(strings.NewReader("servicefile: 0.2.0\nx-owner: team\ninfo:\n  name: orders\n"))
+	decoder.KnownFields(true)
+
+	var sf ServiceFile
+	assert.EqualError(t, decoder.Decode(&sf), `line 2, column 1: unknown field "x-owner"`)
+}
+
+func TestLoadStrict(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "servicefile.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(typoDocument), 0644))
+
+	_, err := Load(path)
+	require.NoError(t, err)
+
+	_, err = LoadStrict(path)
+	assert.ErrorContains(t, err, "failed to parse file "+path+": line 4, column 3")
+
+	_, err = LoadStrict(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read file")
+}
+
+func TestClosest(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"action", "participant", "description", "technology", "proto", "tags", "spec"}
+
+	assert.Equal(t, "participant", closest("Participant", names))
+	assert.Equal(t, "technology", closest("tehcnology", names))
+	assert.Equal(t, "tags", closest("tag", names))
+	assert.Equal(t, "proto", closest("protocol", names))
+	assert.Empty(t, closest("owner", names))
+}
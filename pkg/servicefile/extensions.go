@@ -0,0 +1,503 @@
+package servicefile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExtensionPrefix is the prefix of extension field names.
+const ExtensionPrefix = "x-"
+
+// Extensions holds custom fields that aren't part of the specification, e.g. x-slo-tier.
+type Extensions map[string]any
+
+// IsExtension reports whether the field name is an extension field name.
+func IsExtension(name string) bool {
+	return strings.HasPrefix(name, ExtensionPrefix) && len(name) > len(ExtensionPrefix)
+}
+
+// UnmarshalYAML decodes the info along with its extension fields.
+func (i *Info) UnmarshalYAML(value *yaml.Node) error {
+	type plain Info
+	if err := value.Decode((*plain)(i)); err != nil {
+		return err
+	}
+
+	extensions, err := decodeExtensions(value)
+	if err != nil {
+		return err
+	}
+
+	i.Extensions = extensions
+
+	return nil
+}
+
+// MarshalYAML encodes the info along with its extension fields.
+func (i Info) MarshalYAML() (any, error) {
+	type plain Info
+	return encodeWithExtensions(plain(i), i.Extensions)
+}
+
+// UnmarshalYAML decodes the relationship along with its extension fields.
+func (r *Relationship) UnmarshalYAML(value *yaml.Node) error {
+	type plain Relationship
+	if err := value.Decode((*plain)(r)); err != nil {
+		return err
+	}
+
+	extensions, err := decodeExtensions(value)
+	if err != nil {
+		return err
+	}
+
+	r.Extensions = extensions
+
+	return nil
+}
+
+// MarshalYAML encodes the relationship along with its extension fields.
+func (r Relationship) MarshalYAML() (any, error) {
+	type plain Relationship
+	return encodeWithExtensions(plain(r), r.Extensions)
+}
+
+// UnmarshalJSON decodes the info along with its extension fields.
+func (i *Info) UnmarshalJSON(data []byte) error {
+	type plain Info
+	if err := json.Unmarshal(data, (*plain)(i)); err != nil {
+		return err
+	}
+
+	extensions, err := decodeJSONExtensions(data)
+	if err != nil {
+		return err
+	}
+
+	i.Extensions = extensions
+
+	return nil
+}
+
+// MarshalJSON encodes the info along with its extension fields.
+func (i Info) MarshalJSON() ([]byte, error) {
+	type plain Info
+	return encodeJSONWithExtensions(plain(i), i.Extensions)
+}
+
+// UnmarshalJSON decodes the relationship along with its extension fields.
+func (r *Relationship) UnmarshalJSON(data []byte) error {
+	type plain Relationship
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+
+	extensions, err := decodeJSONExtensions(data)
+	if err != nil {
+		return err
+	}
+
+	r.Extensions = extensions
+
+	return nil
+}
+
+// MarshalJSON encodes the relationship along with its extension fields.
+func (r Relationship) MarshalJSON() ([]byte, error) {
+	type plain Relationship
+	return encodeJSONWithExtensions(plain(r), r.Extensions)
+}
+
+func decodeExtensions(value *yaml.Node) (Extensions, error) {
+	if value.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	var extensions Extensions
+
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		key := value.Content[i].Value
+		if !IsExtension(key) {
+			continue
+		}
+
+		var v any
+		if err := value.Content[i+1].Decode(&v); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+
+		if extensions == nil {
+			extensions = make(Extensions)
+		}
+
+		extensions[key] = v
+	}
+
+	return extensions, nil
+}
+
+func encodeWithExtensions(v any, extensions Extensions) (any, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(extensions))
+	for key := range extensions {
+		if !IsExtension(key) {
+			return nil, fmt.Errorf("extension field %q must start with %q", key, ExtensionPrefix)
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var value yaml.Node
+		if err := value.Encode(extensions[key]); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", key, err)
+		}
+
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &value)
+	}
+
+	return &node, nil
+}
+
+// decodeJSONExtensions decodes the extension fields of a JSON object. Numbers are decoded
+// like YAML does, integers to int and others to float64.
+func decodeJSONExtensions(data []byte) (Extensions, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	var extensions Extensions
+
+	for key, raw := range fields {
+		if !IsExtension(key) {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+
+		var v any
+		if err := decoder.Decode(&v); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+
+		if extensions == nil {
+			extensions = make(Extensions)
+		}
+
+		extensions[key] = fromJSONNumbers(v)
+	}
+
+	return extensions, nil
+}
+
+func fromJSONNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := strconv.Atoi(v.String()); err == nil {
+			return n
+		}
+
+		f, _ := v.Float64()
+
+		return f
+	case []any:
+		for i := range v {
+			v[i] = fromJSONNumbers(v[i])
+		}
+	case map[string]any:
+		for key := range v {
+			v[key] = fromJSONNumbers(v[key])
+		}
+	}
+
+	return v
+}
+
+// encodeJSONWithExtensions encodes v, a JSON object, followed by the extension fields.
+func encodeJSONWithExtensions(v any, extensions Extensions) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(extensions))
+	for key := range extensions {
+		if !IsExtension(key) {
+			return nil, fmt.Errorf("extension field %q must start with %q", key, ExtensionPrefix)
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+
+	for _, key := range keys {
+		value, err := json.Marshal(extensions[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", key, err)
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// ExtensionSchema describes the extension fields allowed on services and relationships.
+type ExtensionSchema struct {
+	Info         map[string]ExtensionField `yaml:"info"`
+	Relationship map[string]ExtensionField `yaml:"relationship"`
+	// Strict rejects extension fields the schema doesn't describe.
+	Strict bool `yaml:"strict,omitempty"`
+
+	// patterns caches the compiled patterns of the fields by their source.
+	patterns sync.Map
+}
+
+// extensionTypes are the types of extension fields.
+var extensionTypes = []string{"string", "number", "integer", "boolean", "array", "object"}
+
+// ExtensionField describes a single extension field.
+type ExtensionField struct {
+	Description string `yaml:"description,omitempty"`
+	// Type is one of string, number, integer, boolean, array or object.
+	Type     string `yaml:"type,omitempty"`
+	Required bool   `yaml:"required,omitempty"`
+	Enum     []any  `yaml:"enum,omitempty"`
+	// Pattern is a regular expression string values must match.
+	Pattern string `yaml:"pattern,omitempty"`
+}
+
+// LoadExtensionSchema reads an ExtensionSchema from a YAML file at the given path.
+func LoadExtensionSchema(path string) (*ExtensionSchema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	var schema ExtensionSchema
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", path, err)
+	}
+
+	if err := schema.check(); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", path, err)
+	}
+
+	return &schema, nil
+}
+
+// check reports the first invalid field of the schema: a field name without the extension
+// prefix, an unknown type or a pattern that doesn't compile.
+func (s *ExtensionSchema) check() error {
+	for _, object := range []struct {
+		name   string
+		fields map[string]ExtensionField
+	}{{"info", s.Info}, {"relationship", s.Relationship}} {
+		name, fields := object.name, object.fields
+
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			field := fields[key]
+
+			if !IsExtension(key) {
+				return fmt.Errorf("%s field %q must start with %q", name, key, ExtensionPrefix)
+			}
+
+			if field.Type != "" && !slices.Contains(extensionTypes, field.Type) {
+				return fmt.Errorf("%s field %q: unsupported type %q, expected one of %s",
+					name, key, field.Type, strings.Join(extensionTypes, ", "))
+			}
+
+			if _, err := s.pattern(field.Pattern); err != nil {
+				return fmt.Errorf("%s field %q pattern: %w", name, key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// pattern returns the compiled pattern, compiling it once.
+func (s *ExtensionSchema) pattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := s.patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	s.patterns.Store(pattern, re)
+
+	return re, nil
+}
+
+// Validate checks the extension fields of the service file against the schema.
+// It fails without checking the fields when the schema itself is invalid.
+func (s *ExtensionSchema) Validate(sf *ServiceFile) error {
+	if err := s.check(); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+
+	var errs []error
+
+	errs = append(errs, s.validate(sf.Info.Name, s.Info, sf.Info.Extensions)...)
+
+	for _, r := range sf.Relationships {
+		context := fmt.Sprintf("%s: %s %s", sf.Info.Name, r.Action, r.Participant)
+		errs = append(errs, s.validate(strings.TrimSpace(context), s.Relationship, r.Extensions)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *ExtensionSchema) validate(context string, fields map[string]ExtensionField, extensions Extensions) []error {
+	var errs []error
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := fields[key]
+
+		value, ok := extensions[key]
+		if !ok {
+			if field.Required {
+				errs = append(errs, fmt.Errorf("%s: %s is required", context, key))
+			}
+
+			continue
+		}
+
+		if err := field.validate(value, s); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", context, key, err))
+		}
+	}
+
+	if s.Strict {
+		unknown := make([]string, 0)
+		for key := range extensions {
+			if _, ok := fields[key]; !ok {
+				unknown = append(unknown, key)
+			}
+		}
+
+		sort.Strings(unknown)
+
+		for _, key := range unknown {
+			errs = append(errs, fmt.Errorf("%s: %s is not described by the schema", context, key))
+		}
+	}
+
+	return errs
+}
+
+// validate checks the value against the field, with the patterns compiled by the schema.
+func (f ExtensionField) validate(value any, s *ExtensionSchema) error {
+	if f.Type != "" && !matchesType(f.Type, value) {
+		return fmt.Errorf("expected %s, got %T", f.Type, value)
+	}
+
+	if len(f.Enum) > 0 {
+		found := false
+		for _, allowed := range f.Enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("%v is not one of %v", value, f.Enum)
+		}
+	}
+
+	if f.Pattern != "" {
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected string to match pattern, got %T", value)
+		}
+
+		re, err := s.pattern(f.Pattern)
+		if err != nil {
+			return err
+		}
+
+		if !re.MatchString(str) {
+			return fmt.Errorf("%q does not match pattern %s", str, f.Pattern)
+		}
+	}
+
+	return nil
+}
+
+func matchesType(typ string, value any) bool {
+	switch typ {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "integer":
+		_, ok := value.(int)
+		return ok
+	case "number":
+		switch value.(type) {
+		case int, float64:
+			return true
+		}
+		return false
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	}
+
+	return false
+}
@@ -0,0 +1,187 @@
+package servicefile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExtensionsMarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	sf := &ServiceFile{
+		Version: Version,
+		Info: Info{
+			Name: "orders",
+			Extensions: Extensions{
+				"x-slo-tier":  "gold",
+				"x-lifecycle": "production",
+			},
+		},
+		Relationships: []Relationship{
+			{
+				Action:      RelationshipActionUses,
+				Participant: "database",
+				Technology:  "postgresql",
+				Extensions: Extensions{
+					"x-regions": []any{"eu", "us"},
+				},
+			},
+		},
+	}
+
+	data, err := yaml.Marshal(sf)
+	require.NoError(t, err)
+
+	assert.Equal(t, `servicefile: `+Version+`
+info:
+    name: orders
+    description: ""
+    x-lifecycle: production
+    x-slo-tier: gold
+relationships:
+    - action: uses
+      participant: database
+      technology: postgresql
+      x-regions:
+        - eu
+        - us
+`, string(data))
+
+	var got ServiceFile
+	require.NoError(t, yaml.Unmarshal(data, &got))
+	assert.Equal(t, sf, &got)
+}
+
+func TestExtensionsMarshalYAMLInvalidKey(t *testing.T) {
+	t.Parallel()
+
+	_, err := yaml.Marshal(Info{Name: "orders", Extensions: Extensions{"slo-tier": "gold"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `must start with "x-"`)
+}
+
+func TestExtensionSchemaValidate(t *testing.T) {
+	t.Parallel()
+
+	schemaContent := `
+info:
+  x-slo-tier:
+    type: string
+    enum: [gold, silver, bronze]
+    required: true
+  x-runbook:
+    type: string
+    pattern: ^https://
+relationship:
+  x-replicas:
+    type: integer
+strict: true
+`
+
+	tests := []struct {
+		name        string
+		sf          *ServiceFile
+		errContains []string
+	}{
+		{
+			name: "valid",
+			sf: &ServiceFile{
+				Info: Info{
+					Name:       "orders",
+					Extensions: Extensions{"x-slo-tier": "gold", "x-runbook": "https://runbooks.example.com"},
+				},
+				Relationships: []Relationship{
+					{Action: RelationshipActionUses, Participant: "database", Extensions: Extensions{"x-replicas": 3}},
+				},
+			},
+		},
+		{
+			name: "missing required field",
+			sf: &ServiceFile{
+				Info: Info{Name: "orders"},
+			},
+			errContains: []string{"orders: x-slo-tier is required"},
+		},
+		{
+			name: "invalid values",
+			sf: &ServiceFile{
+				Info: Info{
+					Name:       "orders",
+					Extensions: Extensions{"x-slo-tier": "platinum", "x-runbook": "ftp://runbooks", "x-unknown": 1},
+				},
+				Relationships: []Relationship{
+					{Action: RelationshipActionUses, Participant: "database", Extensions: Extensions{"x-replicas": "three"}},
+				},
+			},
+			errContains: []string{
+				"orders: x-slo-tier: platinum is not one of [gold silver bronze]",
+				`orders: x-runbook: "ftp://runbooks" does not match pattern ^https://`,
+				"orders: x-unknown is not described by the schema",
+				"orders: uses database: x-replicas: expected integer, got string",
+			},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "schema.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(schemaContent), 0644))
+
+	schema, err := LoadExtensionSchema(path)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate(tt.sf)
+
+			if len(tt.errContains) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, contains := range tt.errContains {
+				assert.Contains(t, err.Error(), contains)
+			}
+		})
+	}
+}
+
+func TestLoadExtensionSchemaInvalid(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "schema.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("info:\n  slo-tier:\n    type: string\n"), 0644))
+
+	_, err := LoadExtensionSchema(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `info field "slo-tier" must start with "x-"`)
+}
+
+func TestLoadExtensionSchemaInvalidType(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "schema.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("relationship:\n  x-replicas:\n    type: int\n"), 0644))
+
+	_, err := LoadExtensionSchema(path)
+	assert.ErrorContains(t, err, `relationship field "x-replicas": unsupported type "int", expected one of string, number, integer, boolean, array, object`)
+}
+
+func TestExtensionSchemaValidateInvalidSchema(t *testing.T) {
+	t.Parallel()
+
+	sf := &ServiceFile{Info: Info{Name: "orders", Extensions: Extensions{"x-runbook": "https://runbooks.example.com"}}}
+
+	schema := &ExtensionSchema{Info: map[string]ExtensionField{"x-runbook": {Type: "string", Pattern: "^https://("}}}
+	assert.ErrorContains(t, schema.Validate(sf), `invalid schema: info field "x-runbook" pattern: error parsing regexp`)
+
+	schema = &ExtensionSchema{Info: map[string]ExtensionField{"x-runbook": {Type: "str"}}}
+	assert.ErrorContains(t, schema.Validate(sf), `unsupported type "str"`)
+
+	schema = &ExtensionSchema{Info: map[string]ExtensionField{"x-runbook": {Type: "string", Pattern: "^https://"}}}
+	assert.NoError(t, schema.Validate(sf))
+}
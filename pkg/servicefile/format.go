@@ -0,0 +1,118 @@
+package servicefile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format represents a serialization format of service files.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatTOML Format = "toml"
+)
+
+// ParseFormat returns the format of the given name, e.g. json.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "json":
+		return FormatJSON, nil
+	case "toml":
+		return FormatTOML, nil
+	}
+
+	return "", fmt.Errorf("unsupported format %q, supported formats: yaml, json, toml", name)
+}
+
+// FormatFromPath returns the format of a file by its extension, .yaml or .yml, .json or .toml.
+// Files with other extensions, and Stdin, are YAML files.
+func FormatFromPath(path string) Format {
+	if format, err := ParseFormat(strings.TrimPrefix(filepath.Ext(path), ".")); err == nil {
+		return format
+	}
+
+	return FormatYAML
+}
+
+// Marshal encodes the service file in the given format. The fields have the same names
+// in every format, x-* extension fields included.
+func (sf *ServiceFile) Marshal(format Format) ([]byte, error) {
+	// Relationships are required, so an empty list is written rather than none or null.
+	if sf.Relationships == nil {
+		normalized := *sf
+		normalized.Relationships = []Relationship{}
+		sf = &normalized
+	}
+
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(sf)
+	case FormatJSON:
+		data, err := json.MarshalIndent(sf, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(data, '\n'), nil
+	case FormatTOML:
+		// TOML is encoded from the YAML document, which holds the extension fields inline.
+		var node yaml.Node
+		if err := node.Encode(sf); err != nil {
+			return nil, err
+		}
+
+		var doc map[string]any
+		if err := node.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+
+		encoder := toml.NewEncoder(&buf)
+		encoder.Indent = ""
+
+		if err := encoder.Encode(doc); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// UnmarshalNode decodes a document in the given format to a YAML node, e.g. to Migrate it.
+// JSON documents are YAML documents already.
+func UnmarshalNode(data []byte, format Format) (*yaml.Node, error) {
+	var node yaml.Node
+
+	switch format {
+	case FormatYAML, FormatJSON:
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+	case FormatTOML:
+		var doc map[string]any
+		if _, err := toml.Decode(string(data), &doc); err != nil {
+			return nil, err
+		}
+
+		if err := node.Encode(doc); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	return &node, nil
+}
@@ -0,0 +1,212 @@
+package servicefile
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testServiceFile() *ServiceFile {
+	return &ServiceFile{
+		Version: Version,
+		Info: Info{
+			Name:        "orders",
+			Description: "Manages orders",
+			System:      "shop",
+			Owner:       "team-orders",
+			Tags:        []string{"core", "payments"},
+			Extensions: Extensions{
+				"x-tier":    1,
+				"x-ratio":   0.5,
+				"x-public":  true,
+				"x-regions": []any{"eu", "us"},
+				"x-slo":     map[string]any{"availability": 99.9, "latency": "200ms"},
+			},
+		},
+		Relationships: []Relationship{
+			{
+				Action:      RelationshipActionUses,
+				Participant: "PostgreSQL",
+				Technology:  "postgresql",
+				External:    true,
+				Extensions:  Extensions{"x-criticality": "high"},
+			},
+			{
+				Action:      RelationshipActionSends,
+				Participant: "Kafka",
+				Technology:  "kafka",
+				Channel:     &Channel{Name: "orders.events", Messages: []string{"OrderCreated"}},
+			},
+			{
+				Action:     RelationshipActionReplies,
+				Technology: "http-server",
+				Proto:      "http",
+				Operations: []string{"GET /orders/{id}"},
+				Spec:       "openapi.yaml",
+			},
+		},
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []Format{FormatYAML, FormatJSON, FormatTOML} {
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			sf := testServiceFile()
+
+			data, err := sf.Marshal(format)
+			require.NoError(t, err)
+
+			path := filepath.Join(t.TempDir(), "servicefile."+string(format))
+			require.NoError(t, os.WriteFile(path, data, 0644))
+
+			got, err := LoadStrict(path)
+			require.NoError(t, err)
+			assert.Equal(t, sf, got)
+
+			again, err := got.Marshal(format)
+			require.NoError(t, err)
+			assert.Equal(t, string(data), string(again))
+		})
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	sf := testServiceFile()
+	sf.Relationships = sf.Relationships[:1]
+
+	data, err := json.Marshal(sf)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"servicefile": "0.2.0",
+		"info": {
+			"name": "orders",
+			"description": "Manages orders",
+			"system": "shop",
+			"owner": "team-orders",
+			"tags": ["core", "payments"],
+			"x-public": true,
+			"x-ratio": 0.5,
+			"x-regions": ["eu", "us"],
+			"x-slo": {"availability": 99.9, "latency": "200ms"},
+			"x-tier": 1
+		},
+		"relationships": [
+			{
+				"action": "uses",
+				"participant": "PostgreSQL",
+				"technology": "postgresql",
+				"external": true,
+				"x-criticality": "high"
+			}
+		]
+	}`, string(data))
+
+	var got ServiceFile
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, sf, &got)
+}
+
+func TestMarshalInvalidExtension(t *testing.T) {
+	t.Parallel()
+
+	sf := testServiceFile()
+	sf.Info.Extensions = Extensions{"tier": 1}
+
+	for _, format := range []Format{FormatYAML, FormatJSON, FormatTOML} {
+		_, err := sf.Marshal(format)
+		assert.ErrorContains(t, err, `extension field "tier" must start with "x-"`, format)
+	}
+}
+
+func TestMarshalNoRelationships(t *testing.T) {
+	t.Parallel()
+
+	sf := &ServiceFile{Version: Version, Info: Info{Name: "orders"}}
+
+	for format, want := range map[Format]string{
+		FormatYAML: "relationships: []\n",
+		FormatJSON: `"relationships": []`,
+		FormatTOML: "relationships = []\n",
+	} {
+		data, err := sf.Marshal(format)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), want, format)
+
+		decoder := NewDecoder(bytes.NewReader(data))
+		decoder.Format(format)
+
+		var loaded ServiceFile
+		require.NoError(t, decoder.Decode(&loaded))
+		assert.Equal(t, []Relationship{}, loaded.Relationships, format)
+	}
+
+	assert.Nil(t, sf.Relationships, "the service file is left as it is")
+}
+
+func TestFormatFromPath(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]Format{
+		"servicefile.yaml":    FormatYAML,
+		"servicefile.yml":     FormatYAML,
+		"docs/orders.JSON":    FormatJSON,
+		"servicefile.toml":    FormatTOML,
+		"servicefile":         FormatYAML,
+		"servicefile.yaml.in": FormatYAML,
+		Stdin:                 FormatYAML,
+	}
+
+	for path, want := range tests {
+		assert.Equal(t, want, FormatFromPath(path), path)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	format, err := ParseFormat("YML")
+	require.NoError(t, err)
+	assert.Equal(t, FormatYAML, format)
+
+	_, err = ParseFormat("xml")
+	assert.EqualError(t, err, `unsupported format "xml", supported formats: yaml, json, toml`)
+}
+
+func TestLoadStrictFormats(t *testing.T) {
+	t.Parallel()
+
+	documents := map[string]string{
+		"servicefile.json": "{\n  \"info\": {\"name\": \"orders\", \"descripton\": \"Manages orders\"}\n}\n",
+		"servicefile.toml": "[info]\nname = \"orders\"\ndescripton = \"Manages orders\"\n",
+	}
+
+	want := map[string]string{
+		"servicefile.json": `line 2, column 30: unknown field "descripton", did you mean "description"?`,
+		"servicefile.toml": `unknown field "descripton", did you mean "description"?`,
+	}
+
+	for name, document := range documents {
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(path, []byte(document), 0644))
+
+		sf, err := Load(path)
+		require.NoError(t, err, name)
+		assert.Equal(t, "orders", sf.Info.Name, name)
+		assert.Equal(t, Version, sf.Version, name)
+
+		_, err = LoadStrict(path)
+		assert.ErrorContains(t, err, want[name], name)
+	}
+}
@@ -0,0 +1,200 @@
+package servicefile
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Conflict reports a field the merged service files set to different values.
+type Conflict struct {
+	// Field is the path of the field, e.g. info.owner or relationships[uses PostgreSQL].description.
+	Field string
+	// Kept is the value of the merged service file.
+	Kept string
+	// Ignored is the value of the service file at Index.
+	Ignored string
+	Index   int
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: kept %q, ignored %q", c.Field, c.Kept, c.Ignored)
+}
+
+// Merge combines service files, e.g. parsed from annotations, imported and written by hand.
+// Earlier service files take precedence: a field is set by the first service file setting
+// it, and later different values are reported as conflicts instead of being silently
+// dropped. Tags, operations, messages and extensions are merged. Relationships with the same
+// action, participant, technology, proto and channel are deduplicated the same way.
+func Merge(serviceFiles ...*ServiceFile) (*ServiceFile, []Conflict) {
+	m := merger{
+		result: &ServiceFile{
+			Version:       Version,
+			Relationships: []Relationship{},
+		},
+	}
+
+	index := make(map[string]int)
+
+	for i, sf := range serviceFiles {
+		m.index = i
+
+		m.info(&m.result.Info, sf.Info)
+
+		for _, rel := range sf.Relationships {
+			key := relationshipKey(rel)
+
+			j, ok := index[key]
+			if !ok {
+				index[key] = len(m.result.Relationships)
+				m.result.Relationships = append(m.result.Relationships, copyRelationship(rel))
+
+				continue
+			}
+
+			m.relationship(&m.result.Relationships[j], rel, "relationships["+key+"]")
+		}
+	}
+
+	m.result.Sort()
+
+	return m.result, m.conflicts
+}
+
+// merger merges fields, collecting the conflicts.
+type merger struct {
+	result    *ServiceFile
+	conflicts []Conflict
+	// index is the index of the service file being merged.
+	index int
+}
+
+func (m *merger) info(dst *Info, src Info) {
+	m.string(&dst.Name, src.Name, "info.name")
+	m.string(&dst.Description, src.Description, "info.description")
+	m.string(&dst.System, src.System, "info.system")
+	m.string(&dst.Owner, src.Owner, "info.owner")
+	m.string(&dst.Repository, src.Repository, "info.repository")
+	dst.Tags = union(dst.Tags, src.Tags, nil)
+	m.extensions(&dst.Extensions, src.Extensions, "info")
+}
+
+func (m *merger) relationship(dst *Relationship, src Relationship, field string) {
+	m.string(&dst.Description, src.Description, field+".description")
+	dst.Tags = union(dst.Tags, src.Tags, nil)
+	dst.External = dst.External || src.External
+	dst.Person = dst.Person || src.Person
+	dst.Operations = union(dst.Operations, src.Operations, NormalizeOperation)
+	m.string(&dst.Spec, src.Spec, field+".spec")
+
+	switch {
+	case dst.Channel == nil:
+		dst.Channel = copyRelationship(Relationship{Channel: src.Channel}).Channel
+	case src.Channel != nil:
+		dst.Channel.Messages = union(dst.Channel.Messages, src.Channel.Messages, nil)
+		m.string(&dst.Channel.Schema, src.Channel.Schema, field+".channel.schema")
+	}
+
+	m.extensions(&dst.Extensions, src.Extensions, field)
+}
+
+// string sets dst to src if it's empty, or reports a conflict if they differ.
+func (m *merger) string(dst *string, src, field string) {
+	switch {
+	case src == "" || src == *dst:
+	case *dst == "":
+		*dst = src
+	default:
+		m.conflicts = append(m.conflicts, Conflict{Field: field, Kept: *dst, Ignored: src, Index: m.index})
+	}
+}
+
+func (m *merger) extensions(dst *Extensions, src Extensions, field string) {
+	keys := make([]string, 0, len(src))
+	for key := range src {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := src[key]
+
+		existing, ok := (*dst)[key]
+		if !ok {
+			if *dst == nil {
+				*dst = make(Extensions)
+			}
+
+			(*dst)[key] = value
+
+			continue
+		}
+
+		if !reflect.DeepEqual(existing, value) {
+			m.conflicts = append(m.conflicts, Conflict{
+				Field:   field + "." + key,
+				Kept:    fmt.Sprint(existing),
+				Ignored: fmt.Sprint(value),
+				Index:   m.index,
+			})
+		}
+	}
+}
+
+// relationshipKey identifies the relationships merged together, e.g. "uses PostgreSQL postgresql".
+func relationshipKey(rel Relationship) string {
+	var parts []string
+	for _, part := range []string{string(rel.Action), rel.Participant, rel.Technology, rel.Proto, rel.Channel.name()} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// copyRelationship copies a relationship so that merging into it leaves the original untouched.
+func copyRelationship(rel Relationship) Relationship {
+	rel.Tags = append([]string(nil), rel.Tags...)
+	rel.Operations = append([]string(nil), rel.Operations...)
+
+	if rel.Channel != nil {
+		channel := *rel.Channel
+		channel.Messages = append([]string(nil), channel.Messages...)
+		rel.Channel = &channel
+	}
+
+	if rel.Extensions != nil {
+		extensions := make(Extensions, len(rel.Extensions))
+		for key, value := range rel.Extensions {
+			extensions[key] = value
+		}
+
+		rel.Extensions = extensions
+	}
+
+	return rel
+}
+
+// union appends the values of src missing from dst, compared by their normalized form.
+func union(dst, src []string, normalize func(string) string) []string {
+	if normalize == nil {
+		normalize = func(s string) string { return s }
+	}
+
+	known := make(map[string]bool, len(dst))
+	for _, v := range dst {
+		known[normalize(v)] = true
+	}
+
+	for _, v := range src {
+		if !known[normalize(v)] {
+			dst = append(dst, v)
+			known[normalize(v)] = true
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,146 @@
+package servicefile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	annotated := &ServiceFile{
+		Version: Version,
+		Info: Info{
+			Name:        "orders",
+			Description: "Manages orders",
+			Tags:        []string{"core"},
+		},
+		Relationships: []Relationship{
+			{
+				Action:      RelationshipActionUses,
+				Participant: "PostgreSQL",
+				Description: "Stores orders",
+				Technology:  "postgresql",
+			},
+			{
+				Action:      RelationshipActionReplies,
+				Participant: "Customer",
+				Technology:  "http-server",
+				Proto:       "http",
+				Operations:  []string{"GET /orders/{id}"},
+			},
+			{
+				Action:      RelationshipActionSends,
+				Participant: "Kafka",
+				Technology:  "kafka",
+				Channel:     &Channel{Name: "orders.events", Messages: []string{"OrderCreated"}},
+			},
+		},
+	}
+
+	imported := &ServiceFile{
+		Version: Version,
+		Info: Info{
+			Name:        "orders",
+			Description: "Order service",
+			Owner:       "team-orders",
+			Tags:        []string{"core", "payments"},
+			Extensions:  Extensions{"x-tier": 1},
+		},
+		Relationships: []Relationship{
+			{
+				Action:      RelationshipActionUses,
+				Participant: "PostgreSQL",
+				Description: "Stores orders and payments",
+				Technology:  "postgresql",
+				External:    true,
+				Tags:        []string{"rds"},
+			},
+			{
+				Action:      RelationshipActionReplies,
+				Participant: "Customer",
+				Technology:  "http-server",
+				Proto:       "http",
+				Operations:  []string{"get /orders/{id}", "POST /orders"},
+				Spec:        "openapi.yaml",
+			},
+			{
+				Action:      RelationshipActionSends,
+				Participant: "Kafka",
+				Technology:  "kafka",
+				Channel:     &Channel{Name: "orders.events", Messages: []string{"OrderCancelled"}},
+			},
+			{
+				Action:      RelationshipActionSends,
+				Participant: "Kafka",
+				Technology:  "kafka",
+				Channel:     &Channel{Name: "audit"},
+			},
+		},
+	}
+
+	handWritten := &ServiceFile{
+		Info: Info{
+			Name:       "orders",
+			Extensions: Extensions{"x-tier": 2},
+		},
+	}
+
+	merged, conflicts := Merge(annotated, imported, handWritten)
+
+	assert.Equal(t, &ServiceFile{
+		Version: Version,
+		Info: Info{
+			Name:        "orders",
+			Description: "Manages orders",
+			Owner:       "team-orders",
+			Tags:        []string{"core", "payments"},
+			Extensions:  Extensions{"x-tier": 1},
+		},
+		Relationships: []Relationship{
+			{
+				Action:      RelationshipActionReplies,
+				Participant: "Customer",
+				Technology:  "http-server",
+				Proto:       "http",
+				Operations:  []string{"GET /orders/{id}", "POST /orders"},
+				Spec:        "openapi.yaml",
+			},
+			{
+				Action:      RelationshipActionSends,
+				Participant: "Kafka",
+				Technology:  "kafka",
+				Channel:     &Channel{Name: "audit"},
+			},
+			{
+				Action:      RelationshipActionSends,
+				Participant: "Kafka",
+				Technology:  "kafka",
+				Channel:     &Channel{Name: "orders.events", Messages: []string{"OrderCreated", "OrderCancelled"}},
+			},
+			{
+				Action:      RelationshipActionUses,
+				Participant: "PostgreSQL",
+				Description: "Stores orders",
+				Technology:  "postgresql",
+				Tags:        []string{"rds"},
+				External:    true,
+			},
+		},
+	}, merged)
+
+	assert.Equal(t, []Conflict{
+		{Field: "info.description", Kept: "Manages orders", Ignored: "Order service", Index: 1},
+		{
+			Field:   "relationships[uses PostgreSQL postgresql].description",
+			Kept:    "Stores orders",
+			Ignored: "Stores orders and payments",
+			Index:   1,
+		},
+		{Field: "info.x-tier", Kept: "1", Ignored: "2", Index: 2},
+	}, conflicts)
+
+	assert.Equal(t, []string{"OrderCreated"}, annotated.Relationships[2].Channel.Messages)
+	assert.Equal(t, `info.description: kept "Manages orders", ignored "Order service"`, conflicts[0].String())
+}
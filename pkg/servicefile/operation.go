@@ -0,0 +1,104 @@
+package servicefile
+
+import (
+	"sort"
+	"strings"
+)
+
+var httpMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+	"OPTIONS": true,
+	"TRACE":   true,
+	"CONNECT": true,
+}
+
+// IsSync reports whether the action is requesting or replying to requests.
+func (a RelationshipAction) IsSync() bool {
+	return a == RelationshipActionRequests || a == RelationshipActionReplies
+}
+
+// NormalizeOperation collapses whitespace in an operation and upper-cases its HTTP method,
+// so "get  /users/{id}" and "GET /users/{id}" are the same operation.
+func NormalizeOperation(operation string) string {
+	fields := strings.Fields(operation)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	if method := strings.ToUpper(fields[0]); httpMethods[method] {
+		fields[0] = method
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// OperationDependency represents a service requesting an operation of another service.
+type OperationDependency struct {
+	Consumer  string
+	Provider  string
+	Operation string
+	// Known is set when the provider lists the operation in its replies relationships.
+	Known bool
+}
+
+// MatchOperations matches the operations of requests relationships with the operations of
+// replies relationships of the service named by the participant.
+func MatchOperations(serviceFiles []*ServiceFile) []OperationDependency {
+	provided := make(map[string]map[string]bool)
+
+	for _, sf := range serviceFiles {
+		for _, rel := range sf.Relationships {
+			if rel.Action != RelationshipActionReplies {
+				continue
+			}
+
+			if provided[sf.Info.Name] == nil {
+				provided[sf.Info.Name] = make(map[string]bool)
+			}
+
+			for _, op := range rel.Operations {
+				provided[sf.Info.Name][NormalizeOperation(op)] = true
+			}
+		}
+	}
+
+	var deps []OperationDependency
+
+	for _, sf := range serviceFiles {
+		for _, rel := range sf.Relationships {
+			if rel.Action != RelationshipActionRequests {
+				continue
+			}
+
+			for _, op := range rel.Operations {
+				op = NormalizeOperation(op)
+
+				deps = append(deps, OperationDependency{
+					Consumer:  sf.Info.Name,
+					Provider:  rel.Participant,
+					Operation: op,
+					Known:     provided[rel.Participant][op],
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(deps, func(i, j int) bool {
+		if deps[i].Provider != deps[j].Provider {
+			return deps[i].Provider < deps[j].Provider
+		}
+
+		if deps[i].Operation != deps[j].Operation {
+			return deps[i].Operation < deps[j].Operation
+		}
+
+		return deps[i].Consumer < deps[j].Consumer
+	})
+
+	return deps
+}
@@ -0,0 +1,77 @@
+package servicefile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeOperation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "GET /users/{id}", expected: "GET /users/{id}"},
+		{input: "  get   /users ", expected: "GET /users"},
+		{input: "user.v1.UserService/GetUser", expected: "user.v1.UserService/GetUser"},
+		{input: "query  GetUser", expected: "query GetUser"},
+		{input: " ", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeOperation(tt.input))
+		})
+	}
+}
+
+func TestMatchOperations(t *testing.T) {
+	t.Parallel()
+
+	serviceFiles := []*ServiceFile{
+		{
+			Info: Info{Name: "users"},
+			Relationships: []Relationship{
+				{
+					Action:     RelationshipActionReplies,
+					Technology: "http-server",
+					Operations: []string{"GET /users/{id}", "POST /users"},
+				},
+			},
+		},
+		{
+			Info: Info{Name: "orders"},
+			Relationships: []Relationship{
+				{
+					Action:      RelationshipActionRequests,
+					Participant: "users",
+					Operations:  []string{"get /users/{id}", "DELETE /users/{id}"},
+				},
+				{
+					Action:      RelationshipActionRequests,
+					Participant: "payments",
+					Operations:  []string{"POST /charges"},
+				},
+			},
+		},
+		{
+			Info: Info{Name: "billing"},
+			Relationships: []Relationship{
+				{
+					Action:      RelationshipActionRequests,
+					Participant: "users",
+					Operations:  []string{"GET /users/{id}"},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []OperationDependency{
+		{Consumer: "orders", Provider: "payments", Operation: "POST /charges", Known: false},
+		{Consumer: "orders", Provider: "users", Operation: "DELETE /users/{id}", Known: false},
+		{Consumer: "billing", Provider: "users", Operation: "GET /users/{id}", Known: true},
+		{Consumer: "orders", Provider: "users", Operation: "GET /users/{id}", Known: true},
+	}, MatchOperations(serviceFiles))
+}
@@ -0,0 +1,233 @@
+package servicefile
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// OverlayFile is the name of the overlay file applied to the service files parsed from a directory.
+	OverlayFile = "servicefile.override.yaml"
+	// AllServices is the name of the overlay service patch applied to every service.
+	AllServices = "*"
+)
+
+// Overlay patches service files with data managed outside of the code, e.g. owners and
+// systems managed by a platform team. Patches are applied in a strategic merge style,
+// so the service files are patched rather than replaced.
+type Overlay struct {
+	// Services maps service names to their patches, the patch of AllServices applies to every
+	// service before its own patch.
+	Services map[string]ServicePatch `yaml:"services"`
+}
+
+// ServicePatch patches a service file.
+type ServicePatch struct {
+	// Info overrides the fields of the info it sets, its tags and extensions are added.
+	Info Info `yaml:"info,omitempty"`
+	// Relationships are merged with the relationships of the service file.
+	Relationships []RelationshipPatch `yaml:"relationships,omitempty"`
+}
+
+// PatchDelete is the $patch value of relationship patches removing the relationship.
+const PatchDelete = "delete"
+
+// RelationshipPatch patches the relationships with the same action and participant, and
+// channel if it sets one, or adds the relationship if there are none.
+type RelationshipPatch struct {
+	Relationship
+	// Patch is set to PatchDelete to remove the relationships instead.
+	Patch string `yaml:"$patch,omitempty"`
+}
+
+// UnmarshalYAML decodes the relationship patch along with its $patch directive.
+func (p *RelationshipPatch) UnmarshalYAML(value *yaml.Node) error {
+	if err := value.Decode(&p.Relationship); err != nil {
+		return err
+	}
+
+	var directive struct {
+		Patch string `yaml:"$patch"`
+	}
+
+	if err := value.Decode(&directive); err != nil {
+		return err
+	}
+
+	if directive.Patch != "" && directive.Patch != PatchDelete {
+		return fmt.Errorf("line %d: unsupported $patch %q, expected %q", value.Line, directive.Patch, PatchDelete)
+	}
+
+	p.Patch = directive.Patch
+
+	return nil
+}
+
+// LoadOverlay reads and parses an overlay from a YAML file at the given path.
+func LoadOverlay(path string) (*Overlay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	var o Overlay
+	if err := yaml.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", path, err)
+	}
+
+	// Service names match case-insensitively, so names differing only in case patch the same services.
+	names := o.names()
+	for i := 1; i < len(names); i++ {
+		if strings.EqualFold(names[i-1], names[i]) {
+			return nil, fmt.Errorf("invalid overlay %s: services %q and %q differ only in case", path, names[i-1], names[i])
+		}
+	}
+
+	return &o, nil
+}
+
+// names returns the names of the services of the overlay sorted case-insensitively,
+// then case-sensitively, leaving out AllServices.
+func (o *Overlay) names() []string {
+	names := make([]string, 0, len(o.Services))
+	for name := range o.Services {
+		if name != AllServices {
+			names = append(names, name)
+		}
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if a, b := strings.ToLower(names[i]), strings.ToLower(names[j]); a != b {
+			return a < b
+		}
+
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// Apply patches the service files and returns the names of the services of the overlay
+// that match none of them. Patches of names differing only in case are applied in the
+// order of their names.
+func (o *Overlay) Apply(serviceFiles []*ServiceFile) []string {
+	names := o.names()
+	matched := make(map[string]bool)
+
+	for _, sf := range serviceFiles {
+		if patch, ok := o.Services[AllServices]; ok {
+			patch.apply(sf)
+		}
+
+		for _, name := range names {
+			if strings.EqualFold(name, sf.Info.Name) {
+				o.Services[name].apply(sf)
+				matched[name] = true
+			}
+		}
+	}
+
+	var unmatched []string
+	for _, name := range names {
+		if !matched[name] {
+			unmatched = append(unmatched, name)
+		}
+	}
+
+	sort.Strings(unmatched)
+
+	return unmatched
+}
+
+func (p ServicePatch) apply(sf *ServiceFile) {
+	info := &sf.Info
+	setString(&info.Description, p.Info.Description)
+	setString(&info.System, p.Info.System)
+	setString(&info.Owner, p.Info.Owner)
+	setString(&info.Repository, p.Info.Repository)
+	info.Tags = union(info.Tags, p.Info.Tags, nil)
+	setExtensions(&info.Extensions, p.Info.Extensions)
+
+	for _, rp := range p.Relationships {
+		rp.apply(sf)
+	}
+
+	sf.Sort()
+}
+
+func (p RelationshipPatch) apply(sf *ServiceFile) {
+	found := false
+	relationships := sf.Relationships[:0]
+
+	for _, rel := range sf.Relationships {
+		if !p.matches(rel) {
+			relationships = append(relationships, rel)
+			continue
+		}
+
+		found = true
+
+		if p.Patch == PatchDelete {
+			continue
+		}
+
+		setString(&rel.Description, p.Description)
+		setString(&rel.Technology, p.Technology)
+		setString(&rel.Proto, p.Proto)
+		setString(&rel.Spec, p.Spec)
+		rel.Tags = union(rel.Tags, p.Tags, nil)
+		rel.External = rel.External || p.External
+		rel.Person = rel.Person || p.Person
+		rel.Operations = union(rel.Operations, p.Operations, NormalizeOperation)
+		setExtensions(&rel.Extensions, p.Extensions)
+
+		if p.Channel != nil {
+			channel := copyRelationship(Relationship{Channel: rel.Channel}).Channel
+			if channel == nil {
+				channel = &Channel{Name: p.Channel.Name}
+			}
+
+			channel.Messages = union(channel.Messages, p.Channel.Messages, nil)
+			setString(&channel.Schema, p.Channel.Schema)
+			rel.Channel = channel
+		}
+
+		relationships = append(relationships, rel)
+	}
+
+	sf.Relationships = relationships
+
+	if !found && p.Patch != PatchDelete {
+		sf.Relationships = append(sf.Relationships, copyRelationship(p.Relationship))
+	}
+}
+
+func (p RelationshipPatch) matches(rel Relationship) bool {
+	if rel.Action != p.Action || !strings.EqualFold(rel.Participant, p.Participant) {
+		return false
+	}
+
+	return p.Channel.name() == "" || p.Channel.name() == rel.Channel.name()
+}
+
+// setString sets dst to src unless src is empty.
+func setString(dst *string, src string) {
+	if src != "" {
+		*dst = src
+	}
+}
+
+// setExtensions sets the extension fields of src on dst.
+func setExtensions(dst *Extensions, src Extensions) {
+	for key, value := range src {
+		if *dst == nil {
+			*dst = make(Extensions)
+		}
+
+		(*dst)[key] = value
+	}
+}
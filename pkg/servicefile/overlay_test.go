@@ -0,0 +1,122 @@
+package servicefile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverlay(t *testing.T) {
+	t.Parallel()
+
+	overlay, err := LoadOverlay(filepath.Join("testdata", OverlayFile))
+	require.NoError(t, err)
+
+	orders := &ServiceFile{
+		Version: Version,
+		Info: Info{
+			Name:  "orders",
+			Owner: "someone",
+			Tags:  []string{"core"},
+		},
+		Relationships: []Relationship{
+			{Action: RelationshipActionUses, Participant: "PostgreSQL", Technology: "postgresql"},
+			{Action: RelationshipActionUses, Participant: "Redis", Technology: "redis"},
+			{
+				Action:      RelationshipActionSends,
+				Participant: "Kafka",
+				Technology:  "kafka",
+				Channel:     &Channel{Name: "orders.events", Messages: []string{"OrderCreated"}},
+			},
+			{
+				Action:      RelationshipActionSends,
+				Participant: "Kafka",
+				Technology:  "kafka",
+				Channel:     &Channel{Name: "audit"},
+			},
+		},
+	}
+	shipping := &ServiceFile{
+		Version:       Version,
+		Info:          Info{Name: "shipping"},
+		Relationships: []Relationship{},
+	}
+
+	unmatched := overlay.Apply([]*ServiceFile{orders, shipping})
+	assert.Equal(t, []string{"payments"}, unmatched)
+
+	assert.Equal(t, &ServiceFile{
+		Version: Version,
+		Info: Info{
+			Name:       "orders",
+			System:     "shop",
+			Owner:      "team-orders",
+			Tags:       []string{"core", "managed"},
+			Extensions: Extensions{"x-tier": 1},
+		},
+		Relationships: []Relationship{
+			{
+				Action:      RelationshipActionSends,
+				Participant: "Kafka",
+				Technology:  "kafka",
+				Channel:     &Channel{Name: "audit"},
+			},
+			{
+				Action:      RelationshipActionSends,
+				Participant: "Kafka",
+				Technology:  "kafka",
+				Channel:     &Channel{Name: "orders.events", Messages: []string{"OrderCreated"}, Schema: "orders-value"},
+			},
+			{
+				Action:      RelationshipActionUses,
+				Participant: "PostgreSQL",
+				Technology:  "postgresql",
+				Tags:        []string{"rds"},
+				External:    true,
+			},
+			{Action: RelationshipActionUses, Participant: "Vault", Technology: "vault", External: true},
+		},
+	}, orders)
+
+	assert.Equal(t, Info{Name: "shipping", System: "shop", Tags: []string{"managed"}}, shipping.Info)
+
+	// Applying the overlay again changes nothing.
+	overlay.Apply([]*ServiceFile{orders})
+	assert.Len(t, orders.Relationships, 4)
+	assert.Equal(t, []string{"core", "managed"}, orders.Info.Tags)
+}
+
+func TestLoadOverlayInvalidPatch(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), OverlayFile)
+	require.NoError(t, os.WriteFile(path, []byte("services:\n  orders:\n    relationships:\n      - action: uses\n        participant: Redis\n        $patch: replace\n"), 0644))
+
+	_, err := LoadOverlay(path)
+	assert.ErrorContains(t, err, `unsupported $patch "replace"`)
+}
+
+func TestOverlayNamesDifferingInCase(t *testing.T) {
+	t.Parallel()
+
+	overlay := &Overlay{Services: map[string]ServicePatch{
+		"orders": {Info: Info{Owner: "team-orders"}},
+		"Orders": {Info: Info{Owner: "team-shop"}},
+		"ORDERS": {Info: Info{System: "shop"}},
+	}}
+
+	for range 20 {
+		sf := &ServiceFile{Info: Info{Name: "Orders"}}
+		assert.Empty(t, overlay.Apply([]*ServiceFile{sf}))
+		assert.Equal(t, Info{Name: "Orders", System: "shop", Owner: "team-orders"}, sf.Info)
+	}
+
+	path := filepath.Join(t.TempDir(), OverlayFile)
+	require.NoError(t, os.WriteFile(path, []byte("services:\n  orders:\n    info:\n      owner: a\n  Orders:\n    info:\n      owner: b\n"), 0644))
+
+	_, err := LoadOverlay(path)
+	assert.ErrorContains(t, err, `services "Orders" and "orders" differ only in case`)
+}
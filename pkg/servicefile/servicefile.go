@@ -2,41 +2,59 @@ package servicefile
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sort"
-
-	"gopkg.in/yaml.v3"
 )
 
-const Version string = "0.1.0"
+const Version string = "0.2.0"
 
 // ServiceFile represents a service file.
 type ServiceFile struct {
-	Version       string         `yaml:"servicefile"`
-	Info          Info           `yaml:"info"`
-	Relationships []Relationship `yaml:"relationships"`
+	Version       string         `yaml:"servicefile" json:"servicefile"`
+	Info          Info           `yaml:"info" json:"info"`
+	Relationships []Relationship `yaml:"relationships" json:"relationships"`
 }
 
 // Info represents a info about service.
 type Info struct {
-	Name        string   `yaml:"name"`
-	Description string   `yaml:"description"`
-	System      string   `yaml:"system,omitempty"`
-	Owner       string   `yaml:"owner,omitempty"`
-	Repository  string   `yaml:"repository,omitempty"`
-	Tags        []string `yaml:"tags,omitempty"`
+	Name        string   `yaml:"name" json:"name"`
+	Description string   `yaml:"description" json:"description"`
+	System      string   `yaml:"system,omitempty" json:"system,omitempty"`
+	Owner       string   `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Repository  string   `yaml:"repository,omitempty" json:"repository,omitempty"`
+	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	// Extensions holds the x-* fields of the service.
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // Relationship represents a relationship between current service and external components.
 type Relationship struct {
-	Action      RelationshipAction `yaml:"action"`
-	Participant string             `yaml:"participant,omitempty"`
-	Description string             `yaml:"description,omitempty"`
-	Technology  string             `yaml:"technology"`
-	Proto       string             `yaml:"proto,omitempty"`
-	Tags        []string           `yaml:"tags,omitempty"`
-	External    bool               `yaml:"external,omitempty"`
-	Person      bool               `yaml:"person,omitempty"`
+	Action      RelationshipAction `yaml:"action" json:"action"`
+	Participant string             `yaml:"participant,omitempty" json:"participant,omitempty"`
+	Description string             `yaml:"description,omitempty" json:"description,omitempty"`
+	Technology  string             `yaml:"technology" json:"technology"`
+	Proto       string             `yaml:"proto,omitempty" json:"proto,omitempty"`
+	Tags        []string           `yaml:"tags,omitempty" json:"tags,omitempty"`
+	External    bool               `yaml:"external,omitempty" json:"external,omitempty"`
+	Person      bool               `yaml:"person,omitempty" json:"person,omitempty"`
+	// Channel is the message channel of sends and receives relationships.
+	Channel *Channel `yaml:"channel,omitempty" json:"channel,omitempty"`
+	// Operations lists the operations exposed by replies relationships or called by requests
+	// relationships, e.g. "GET /users/{id}", "user.v1.UserService/GetUser" or "query GetUser".
+	Operations []string `yaml:"operations,omitempty" json:"operations,omitempty"`
+	// Spec is the path of an OpenAPI document describing the API of replies relationships.
+	Spec string `yaml:"spec,omitempty" json:"spec,omitempty"`
+	// Extensions holds the x-* fields of the relationship.
+	Extensions Extensions `yaml:"-" json:"-"`
+}
+
+// Channel represents a message channel, e.g. a Kafka topic or a RabbitMQ exchange and routing key.
+type Channel struct {
+	Name     string   `yaml:"name" json:"name"`
+	Messages []string `yaml:"messages,omitempty" json:"messages,omitempty"`
+	// Schema references the payload schema, e.g. a path or a schema registry subject.
+	Schema string `yaml:"schema,omitempty" json:"schema,omitempty"`
 }
 
 // RelationshipAction represents an action between services.
@@ -53,38 +71,67 @@ const (
 // Sort sorts the relationships in the service file.
 func (sf *ServiceFile) Sort() {
 	sort.Slice(sf.Relationships, func(i, j int) bool {
-		rel1 := sf.Relationships[i]
-		rel2 := sf.Relationships[j]
+		return sf.Relationships[i].less(sf.Relationships[j])
+	})
+}
 
-		if rel1.Action != rel2.Action {
-			return string(rel1.Action) < string(rel2.Action)
-		}
+// less orders relationships by action, participant, technology, proto, channel and description.
+func (r Relationship) less(other Relationship) bool {
+	if r.Action != other.Action {
+		return string(r.Action) < string(other.Action)
+	}
 
-		if rel1.Participant != rel2.Participant {
-			return rel1.Participant < rel2.Participant
-		}
+	if r.Participant != other.Participant {
+		return r.Participant < other.Participant
+	}
 
-		if rel1.Technology != rel2.Technology {
-			return rel1.Technology < rel2.Technology
-		}
+	if r.Technology != other.Technology {
+		return r.Technology < other.Technology
+	}
 
-		if rel1.Proto != rel2.Proto {
-			return rel1.Proto < rel2.Proto
-		}
+	if r.Proto != other.Proto {
+		return r.Proto < other.Proto
+	}
 
-		return rel1.Description < rel2.Description
-	})
+	if r.Channel.name() != other.Channel.name() {
+		return r.Channel.name() < other.Channel.name()
+	}
+
+	return r.Description < other.Description
 }
 
-// Load reads and parses a ServiceFile from a YAML file at the given path.
+// Load reads and parses a ServiceFile from a file at the given path, or from the standard
+// input if the path is Stdin. The format is detected from the extension, see FormatFromPath.
+// Documents of earlier versions are migrated to Version, see Migrate.
 func Load(path string) (*ServiceFile, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	return load(path, false)
+}
+
+// LoadStrict is Load failing on unknown fields, e.g. partcipant instead of participant,
+// see Decoder.KnownFields.
+func LoadStrict(path string) (*ServiceFile, error) {
+	return load(path, true)
+}
+
+func load(path string, knownFields bool) (*ServiceFile, error) {
+	var r io.Reader = os.Stdin
+
+	if path != Stdin {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+		defer f.Close()
+
+		r = f
 	}
 
+	decoder := NewDecoder(r)
+	decoder.Format(FormatFromPath(path))
+	decoder.KnownFields(knownFields)
+
 	var sf ServiceFile
-	if err := yaml.Unmarshal(data, &sf); err != nil {
+	if err := decoder.Decode(&sf); err != nil {
 		return nil, fmt.Errorf("failed to parse file %s: %w", path, err)
 	}
 
@@ -37,7 +37,7 @@ relationships:
     technology: "smtp"
 `,
 			want: &ServiceFile{
-				Version: "0.1.0",
+				Version: Version,
 				Info: Info{
 					Name:        "test-service",
 					Description: "A test service",
@@ -73,7 +73,7 @@ relationships:
     technology: "postgresql"
 `,
 			want: &ServiceFile{
-				Version: "0.1.0",
+				Version: Version,
 				Info: Info{
 					Name:        "user-service",
 					Description: "Handles user authentication and profiles",
@@ -103,7 +103,7 @@ relationships:
     technology: "postgresql"
 `,
 			want: &ServiceFile{
-				Version: "0.1.0",
+				Version: Version,
 				Info: Info{
 					Name:        "user-service",
 					Description: "Handles user authentication and profiles",
@@ -127,7 +127,7 @@ info:
     name: "minimal-service"
 `,
 			want: &ServiceFile{
-				Version: "0.1.0",
+				Version: Version,
 				Info: Info{
 					Name:        "minimal-service",
 					Description: "",
@@ -155,7 +155,7 @@ relationships:
     technology: "rabbitmq"
 `,
 			want: &ServiceFile{
-				Version: "0.1.0",
+				Version: Version,
 				Info: Info{
 					Name:        "complete-service",
 					Description: "Service with all relationship types",
@@ -199,7 +199,7 @@ relationships:
     proto: "tcp"
 `,
 			want: &ServiceFile{
-				Version: "0.1.0",
+				Version: Version,
 				Info: Info{
 					Name:        "api-service",
 					Description: "API service with protocol specifications",
@@ -250,7 +250,7 @@ relationships:
     technology: "postgresql"
 `,
 			want: &ServiceFile{
-				Version: "0.1.0",
+				Version: Version,
 				Info: Info{
 					Name:        "tagged-service",
 					Description: "A service with tags",
@@ -294,7 +294,7 @@ relationships:
     tags: ["messaging", "async"]
 `,
 			want: &ServiceFile{
-				Version: "0.1.0",
+				Version: Version,
 				Info: Info{
 					Name:        "relationship-tagged-service",
 					Description: "A service with tagged relationships",
@@ -348,7 +348,7 @@ relationships:
     external: false
 `,
 			want: &ServiceFile{
-				Version: "0.1.0",
+				Version: Version,
 				Info: Info{
 					Name:        "external-service",
 					Description: "A service with external relationships",
@@ -397,7 +397,7 @@ relationships:
     person: false
 `,
 			want: &ServiceFile{
-				Version: "0.1.0",
+				Version: Version,
 				Info: Info{
 					Name:        "user-service",
 					Description: "A service that replies to people",
@@ -428,6 +428,116 @@ relationships:
 			},
 			wantErr: false,
 		},
+		{
+			name: "servicefile with extension fields",
+			yamlContent: `
+servicefile: "0.1.0"
+info:
+    name: "orders"
+    x-slo-tier: gold
+    x-runbook: https://runbooks.example.com/orders
+    unknown: ignored
+relationships:
+  - action: "uses"
+    participant: "database"
+    technology: "postgresql"
+    x-data-classification: confidential
+    x-replicas: 3
+`,
+			want: &ServiceFile{
+				Version: Version,
+				Info: Info{
+					Name: "orders",
+					Extensions: Extensions{
+						"x-slo-tier": "gold",
+						"x-runbook":  "https://runbooks.example.com/orders",
+					},
+				},
+				Relationships: []Relationship{
+					{
+						Action:      "uses",
+						Participant: "database",
+						Technology:  "postgresql",
+						Extensions: Extensions{
+							"x-data-classification": "confidential",
+							"x-replicas":            3,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "servicefile with channels",
+			yamlContent: `
+servicefile: "0.1.0"
+info:
+    name: "orders"
+relationships:
+  - action: "sends"
+    participant: "Kafka"
+    technology: "kafka"
+    channel:
+        name: "orders.events"
+        messages: ["OrderCreated", "OrderCancelled"]
+        schema: "schemas/order-events.avsc"
+`,
+			want: &ServiceFile{
+				Version: Version,
+				Info: Info{
+					Name: "orders",
+				},
+				Relationships: []Relationship{
+					{
+						Action:      "sends",
+						Participant: "Kafka",
+						Technology:  "kafka",
+						Channel: &Channel{
+							Name:     "orders.events",
+							Messages: []string{"OrderCreated", "OrderCancelled"},
+							Schema:   "schemas/order-events.avsc",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "servicefile with operations",
+			yamlContent: `
+servicefile: "0.1.0"
+info:
+    name: "users"
+relationships:
+  - action: "replies"
+    technology: "http-server"
+    proto: "http"
+    operations:
+      - GET /users/{id}
+      - POST /users
+`,
+			want: &ServiceFile{
+				Version: Version,
+				Info: Info{
+					Name: "users",
+				},
+				Relationships: []Relationship{
+					{
+						Action:     "replies",
+						Technology: "http-server",
+						Proto:      "http",
+						Operations: []string{"GET /users/{id}", "POST /users"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "unsupported major version",
+			yamlContent: "servicefile: \"1.0.0\"\ninfo:\n  name: \"future-service\"\n",
+			wantErr:     true,
+			errContains: "unsupported servicefile version 1.0.0",
+		},
 		{
 			name:        "invalid yaml",
 			yamlContent: `name: "test" invalid: yaml: content`,
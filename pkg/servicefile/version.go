@@ -0,0 +1,216 @@
+package servicefile
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FirstVersion is the version of documents that don't declare one.
+const FirstVersion = "0.1.0"
+
+// versionField is the field documents declare their version in.
+const versionField = "servicefile"
+
+// ErrUnsupportedVersion is returned for documents of another major version than Version.
+var ErrUnsupportedVersion = errors.New("unsupported servicefile version")
+
+// Migration upgrades documents from a version to the next one.
+type Migration struct {
+	From string
+	To   string
+	// Migrate rewrites the mapping node of a document of version From to version To.
+	// Its version field is updated afterwards.
+	Migrate func(doc *yaml.Node) error
+}
+
+// migrations maps the major.minor version documents are migrated from to their migration.
+var migrations = make(map[string]Migration)
+
+func init() {
+	// 0.2.0 adds the optional channel, operations, spec and x-* fields,
+	// so 0.1.0 documents are valid as is.
+	MustRegisterMigration(Migration{
+		From:    "0.1.0",
+		To:      "0.2.0",
+		Migrate: func(*yaml.Node) error { return nil },
+	})
+}
+
+// RegisterMigration adds a migration to the ones Migrate upgrades documents with.
+// Migrations are identified by the major and minor version they upgrade from.
+func RegisterMigration(m Migration) error {
+	from, err := parseVersion(m.From)
+	if err != nil {
+		return err
+	}
+
+	to, err := parseVersion(m.To)
+	if err != nil {
+		return err
+	}
+
+	if from.major != to.major || from.minor >= to.minor {
+		return fmt.Errorf("migration from %s to %s must upgrade to a later minor version", m.From, m.To)
+	}
+
+	if m.Migrate == nil {
+		return fmt.Errorf("migration from %s has no Migrate function", m.From)
+	}
+
+	if existing, ok := migrations[from.key()]; ok {
+		return fmt.Errorf("migration from %s is already registered to %s", m.From, existing.To)
+	}
+
+	migrations[from.key()] = m
+
+	return nil
+}
+
+// MustRegisterMigration is RegisterMigration panicking on errors, for use in init functions.
+func MustRegisterMigration(m Migration) {
+	if err := RegisterMigration(m); err != nil {
+		panic(err)
+	}
+}
+
+// Migrate upgrades a document step by step to Version and returns the version it declared.
+// Documents without a version are FirstVersion documents, documents of a later minor version
+// are left as is and documents of another major version are refused with ErrUnsupportedVersion.
+func Migrate(doc *yaml.Node) (string, error) {
+	// Empty documents have nothing to migrate.
+	if doc.Kind == 0 {
+		return "", nil
+	}
+
+	if doc.Kind == yaml.DocumentNode {
+		if len(doc.Content) == 0 {
+			return "", nil
+		}
+
+		doc = doc.Content[0]
+	}
+
+	if doc.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("line %d: expected a mapping", doc.Line)
+	}
+
+	value := mappingValue(doc, versionField)
+
+	declared := FirstVersion
+	if value != nil && value.Value != "" {
+		declared = value.Value
+	}
+
+	v, err := parseVersion(declared)
+	if err != nil {
+		return declared, err
+	}
+
+	current, err := parseVersion(Version)
+	if err != nil {
+		return declared, err
+	}
+
+	if v.major != current.major {
+		return declared, fmt.Errorf("%w %s, expected %d.x", ErrUnsupportedVersion, declared, current.major)
+	}
+
+	if !v.less(current) {
+		return declared, nil
+	}
+
+	for v.minor < current.minor {
+		m, ok := migrations[v.key()]
+		if !ok {
+			return declared, fmt.Errorf("no migration from servicefile version %s", v)
+		}
+
+		if err := m.Migrate(doc); err != nil {
+			return declared, fmt.Errorf("failed to migrate from %s to %s: %w", m.From, m.To, err)
+		}
+
+		if v, err = parseVersion(m.To); err != nil {
+			return declared, err
+		}
+	}
+
+	setVersion(doc, value)
+
+	return declared, nil
+}
+
+// setVersion sets the version field of the mapping node to Version, adding it first if it's missing.
+func setVersion(doc, value *yaml.Node) {
+	if value != nil {
+		value.Value = Version
+		value.Tag = "!!str"
+
+		return
+	}
+
+	doc.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: versionField},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: Version},
+	}, doc.Content...)
+}
+
+// mappingValue returns the value of a key of the mapping node, or nil if it's missing.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// version represents a major.minor.patch version.
+type version struct {
+	major, minor, patch int
+}
+
+func parseVersion(s string) (version, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return version{}, fmt.Errorf("invalid servicefile version %q, expected major.minor.patch", s)
+	}
+
+	var numbers [3]int
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return version{}, fmt.Errorf("invalid servicefile version %q, expected major.minor.patch", s)
+		}
+
+		numbers[i] = n
+	}
+
+	return version{major: numbers[0], minor: numbers[1], patch: numbers[2]}, nil
+}
+
+func (v version) less(other version) bool {
+	if v.major != other.major {
+		return v.major < other.major
+	}
+
+	if v.minor != other.minor {
+		return v.minor < other.minor
+	}
+
+	return v.patch < other.patch
+}
+
+// key identifies the migration from the version.
+func (v version) key() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
+
+func (v version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
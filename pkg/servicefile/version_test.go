@@ -0,0 +1,142 @@
+package servicefile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		doc      string
+		declared string
+		expected string
+		err      string
+	}{
+		{
+			name:     "earlier version",
+			doc:      "# Orders\nservicefile: 0.1.0 # migrated\ninfo:\n  name: orders\n",
+			declared: "0.1.0",
+			expected: "# Orders\nservicefile: 0.2.0 # migrated\ninfo:\n    name: orders\n",
+		},
+		{
+			name:     "no version",
+			doc:      "info:\n  name: orders\n",
+			declared: FirstVersion,
+			expected: "servicefile: 0.2.0\ninfo:\n    name: orders\n",
+		},
+		{
+			name:     "current version",
+			doc:      "servicefile: 0.2.0\ninfo:\n  name: orders\n",
+			declared: "0.2.0",
+			expected: "servicefile: 0.2.0\ninfo:\n    name: orders\n",
+		},
+		{
+			name:     "later minor version",
+			doc:      "servicefile: 0.9.0\ninfo:\n  name: orders\n",
+			declared: "0.9.0",
+			expected: "servicefile: 0.9.0\ninfo:\n    name: orders\n",
+		},
+		{
+			name:     "other major version",
+			doc:      "servicefile: 1.0.0\n",
+			declared: "1.0.0",
+			err:      "unsupported servicefile version 1.0.0, expected 0.x",
+		},
+		{
+			name:     "invalid version",
+			doc:      "servicefile: latest\n",
+			declared: "latest",
+			err:      `invalid servicefile version "latest"`,
+		},
+		{
+			name: "not a mapping",
+			doc:  "- servicefile\n",
+			err:  "line 1: expected a mapping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var doc yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tt.doc), &doc))
+
+			declared, err := Migrate(&doc)
+			assert.Equal(t, tt.declared, declared)
+
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			data, err := yaml.Marshal(&doc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(data))
+		})
+	}
+}
+
+func TestMigrateUnsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	var doc yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte("servicefile: 2.1.0\n"), &doc))
+
+	_, err := Migrate(&doc)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestRegisterMigration(t *testing.T) {
+	t.Parallel()
+
+	migrate := func(*yaml.Node) error { return nil }
+
+	tests := []struct {
+		name      string
+		migration Migration
+		err       string
+	}{
+		{
+			name:      "already registered",
+			migration: Migration{From: "0.1.0", To: "0.2.0", Migrate: migrate},
+			err:       "migration from 0.1.0 is already registered to 0.2.0",
+		},
+		{
+			name:      "same minor version",
+			migration: Migration{From: "0.3.0", To: "0.3.1", Migrate: migrate},
+			err:       "must upgrade to a later minor version",
+		},
+		{
+			name:      "downgrade",
+			migration: Migration{From: "0.3.0", To: "0.2.0", Migrate: migrate},
+			err:       "must upgrade to a later minor version",
+		},
+		{
+			name:      "invalid version",
+			migration: Migration{From: "0.3", To: "0.4.0", Migrate: migrate},
+			err:       `invalid servicefile version "0.3"`,
+		},
+		{
+			name:      "no function",
+			migration: Migration{From: "0.3.0", To: "0.4.0"},
+			err:       "has no Migrate function",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.ErrorContains(t, RegisterMigration(tt.migration), tt.err)
+		})
+	}
+}